@@ -0,0 +1,300 @@
+// Package aggregate summarises historic archive records into daily, monthly and yearly figures.
+//
+// Outdoor figures come from one sensor, a Vantage station or an ISS, so a station with several
+// transmitters does not count its rain twice. Pressure comes from the barometer or Vantage console.
+//
+// Day boundaries are taken in the station's own time zone. An archive record is timestamped at
+// the end of its interval, so a record at midnight counts towards the day before.
+package aggregate
+
+import (
+	"sort"
+	"time"
+
+	"github.com/alexhowarth/go-weatherlink"
+)
+
+// Period is the length of time covered by a Summary
+type Period int
+
+// Periods to summarise by
+const (
+	Day Period = iota
+	Month
+	Year
+)
+
+// ParsePeriod returns the Period called day, month or year
+func ParsePeriod(s string) (Period, bool) {
+	switch s {
+	case "day":
+		return Day, true
+	case "month":
+		return Month, true
+	case "year":
+		return Year, true
+	}
+	return 0, false
+}
+
+// String returns the name of the period
+func (p Period) String() string {
+	switch p {
+	case Day:
+		return "day"
+	case Month:
+		return "month"
+	case Year:
+		return "year"
+	}
+	return "unknown"
+}
+
+// start returns the start of the period containing t
+func (p Period) start(t time.Time) time.Time {
+	switch p {
+	case Month:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	case Year:
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// end returns the end of the period starting at t
+func (p Period) end(t time.Time) time.Time {
+	switch p {
+	case Month:
+		return t.AddDate(0, 1, 0)
+	case Year:
+		return t.AddDate(1, 0, 0)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+// Summary holds the figures for one period. Values are in the units of the API and are
+// missing if no record in the period reported them.
+type Summary struct {
+	Period       string            `json:"period"`
+	Start        time.Time         `json:"start"`
+	End          time.Time         `json:"end"`
+	Records      int               `json:"records"` // archive intervals with a record
+	TempHi       weatherlink.Float `json:"temp_hi" unit:"degF"`
+	TempHiAt     time.Time         `json:"temp_hi_at"`
+	TempLo       weatherlink.Float `json:"temp_lo" unit:"degF"`
+	TempLoAt     time.Time         `json:"temp_lo_at"`
+	TempMean     weatherlink.Float `json:"temp_mean" unit:"degF"`
+	HumHi        weatherlink.Float `json:"hum_hi"`
+	HumLo        weatherlink.Float `json:"hum_lo"`
+	BarHi        weatherlink.Float `json:"bar_hi" unit:"inHg"`
+	BarLo        weatherlink.Float `json:"bar_lo" unit:"inHg"`
+	Rainfall     weatherlink.Float `json:"rainfall" unit:"in"`
+	RainRateHi   weatherlink.Float `json:"rain_rate_hi" unit:"in/h"`
+	Et           weatherlink.Float `json:"et" unit:"in"`
+	WindSpeedAvg weatherlink.Float `json:"wind_speed_avg" unit:"mph"`
+	WindGust     weatherlink.Float `json:"wind_gust" unit:"mph"`
+	WindGustDir  weatherlink.Float `json:"wind_gust_dir"`
+	WindGustAt   time.Time         `json:"wind_gust_at"`
+	WindRun      weatherlink.Float `json:"wind_run" unit:"mi"`
+
+	tempSum float64
+	tempN   int
+	windSum float64
+	windN   int
+	seen    map[int64]bool // times of the records counted
+}
+
+// sample is the part of an archive record that is summarised
+type sample struct {
+	tempAvg, tempHi, tempLo weatherlink.Float
+	hum, barHi, barLo       weatherlink.Float
+	rain, rainRateHi, et    weatherlink.Float
+	windAvg, gust, gustDir  weatherlink.Float
+	windRun                 weatherlink.Float
+	outdoor                 bool // from an outdoor sensor rather than a barometer
+}
+
+// sampleOf extracts the summarised values from the archive record types that carry them
+func sampleOf(data weatherlink.SensorData) (sample, bool) {
+	switch d := data.(type) {
+	case *weatherlink.VantageArchive:
+		return sample{
+			tempAvg: d.TempOut, tempHi: d.TempOutHi, tempLo: d.TempOutLo,
+			hum: d.HumOut, barHi: d.Bar, barLo: d.Bar,
+			rain: d.RainfallIn, rainRateHi: d.RainRateHiIn, et: d.Et,
+			windAvg: d.WindSpeedAvg, gust: d.WindSpeedHi, gustDir: compassDegrees(d.WindDirOfHi),
+			windRun: d.WindRun, outdoor: true,
+		}, true
+	case *weatherlink.ISSArchive:
+		return sample{
+			tempAvg: d.TempAvg, tempHi: d.TempHi, tempLo: d.TempLo,
+			hum:  d.HumLast,
+			rain: d.RainfallIn, rainRateHi: d.RainRateHiIn, et: d.Et,
+			windAvg: d.WindSpeedAvg, gust: d.WindSpeedHi, gustDir: d.WindSpeedHiDir,
+			windRun: d.WindRun, outdoor: true,
+		}, true
+	case *weatherlink.BarometerArchive:
+		return sample{barHi: d.BarHi, barLo: d.BarLo}, true
+	}
+	return sample{}, false
+}
+
+// compassDegrees converts a Vantage compass point (0 to 15, with 0 north) into degrees
+func compassDegrees(f weatherlink.Float) weatherlink.Float {
+	if !f.Valid {
+		return f
+	}
+	return weatherlink.NewFloat(f.Value * 22.5)
+}
+
+// add includes a sample taken at t in the summary
+func (s *Summary) add(v sample, t time.Time) {
+	if !s.seen[t.Unix()] {
+		if s.seen == nil {
+			s.seen = make(map[int64]bool)
+		}
+		s.seen[t.Unix()] = true
+		s.Records++
+	}
+
+	if v.tempHi.Valid && (!s.TempHi.Valid || v.tempHi.Value > s.TempHi.Value) {
+		s.TempHi, s.TempHiAt = v.tempHi, t
+	}
+	if v.tempLo.Valid && (!s.TempLo.Valid || v.tempLo.Value < s.TempLo.Value) {
+		s.TempLo, s.TempLoAt = v.tempLo, t
+	}
+	if v.tempAvg.Valid {
+		s.tempSum += v.tempAvg.Value
+		s.tempN++
+		s.TempMean = weatherlink.NewFloat(s.tempSum / float64(s.tempN))
+	}
+	if v.windAvg.Valid {
+		s.windSum += v.windAvg.Value
+		s.windN++
+		s.WindSpeedAvg = weatherlink.NewFloat(s.windSum / float64(s.windN))
+	}
+	if v.gust.Valid && (!s.WindGust.Valid || v.gust.Value > s.WindGust.Value) {
+		s.WindGust, s.WindGustDir, s.WindGustAt = v.gust, v.gustDir, t
+	}
+
+	keepMax(&s.HumHi, v.hum)
+	keepMin(&s.HumLo, v.hum)
+	keepMax(&s.BarHi, v.barHi)
+	keepMin(&s.BarLo, v.barLo)
+	keepMax(&s.RainRateHi, v.rainRateHi)
+	addTo(&s.Rainfall, v.rain)
+	addTo(&s.Et, v.et)
+	addTo(&s.WindRun, v.windRun)
+}
+
+func keepMax(f *weatherlink.Float, v weatherlink.Float) {
+	if v.Valid && (!f.Valid || v.Value > f.Value) {
+		*f = v
+	}
+}
+
+func keepMin(f *weatherlink.Float, v weatherlink.Float) {
+	if v.Valid && (!f.Valid || v.Value < f.Value) {
+		*f = v
+	}
+}
+
+func addTo(f *weatherlink.Float, v weatherlink.Float) {
+	if v.Valid {
+		*f = weatherlink.NewFloat(f.Value + v.Value)
+	}
+}
+
+// Aggregator builds summaries from records added one at a time, so records can be streamed
+// from a weatherlink.HistoricIterator
+type Aggregator struct {
+	// Outdoor is the lsid of the sensor outdoor figures are taken from. If it is 0 the first
+	// Vantage station or ISS added is used, and records of any other are ignored.
+	Outdoor int
+
+	loc       *time.Location
+	period    Period
+	summaries map[time.Time]*Summary
+	order     []time.Time
+}
+
+// New returns an Aggregator summarising by period with boundaries in loc
+func New(loc *time.Location, period Period) *Aggregator {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return &Aggregator{
+		loc:       loc,
+		period:    period,
+		summaries: make(map[time.Time]*Summary),
+	}
+}
+
+// Add includes a record in the summary of its period. Records of types that are not archive
+// records, and outdoor records of sensors other than Outdoor, are ignored.
+func (a *Aggregator) Add(r weatherlink.HistoricRecord) {
+	v, ok := sampleOf(r.Data)
+	if !ok {
+		return
+	}
+	if v.outdoor {
+		if a.Outdoor == 0 {
+			a.Outdoor = r.Lsid
+		}
+		if r.Lsid != a.Outdoor {
+			return
+		}
+	}
+	t := r.Time.In(a.loc)
+	start := a.period.start(t.Add(-time.Second))
+	s, ok := a.summaries[start]
+	if !ok {
+		s = &Summary{
+			Period: a.period.String(),
+			Start:  start,
+			End:    a.period.end(start),
+		}
+		a.summaries[start] = s
+		a.order = append(a.order, start)
+	}
+	s.add(v, t)
+}
+
+// Summaries returns the summary of each period with records, in time order
+func (a *Aggregator) Summaries() []Summary {
+	out := make([]Summary, 0, len(a.order))
+	for _, start := range sortedTimes(a.order) {
+		out = append(out, *a.summaries[start])
+	}
+	return out
+}
+
+func sortedTimes(t []time.Time) []time.Time {
+	s := append([]time.Time(nil), t...)
+	sort.Slice(s, func(a, b int) bool { return s[a].Before(s[b]) })
+	return s
+}
+
+// Records summarises records by period with boundaries in loc
+func Records(records []weatherlink.HistoricRecord, loc *time.Location, period Period) []Summary {
+	a := New(loc, period)
+	for _, r := range records {
+		a.Add(r)
+	}
+	return a.Summaries()
+}
+
+// Daily summarises records by day in loc
+func Daily(records []weatherlink.HistoricRecord, loc *time.Location) []Summary {
+	return Records(records, loc, Day)
+}
+
+// Monthly summarises records by month in loc
+func Monthly(records []weatherlink.HistoricRecord, loc *time.Location) []Summary {
+	return Records(records, loc, Month)
+}
+
+// Yearly summarises records by year in loc
+func Yearly(records []weatherlink.HistoricRecord, loc *time.Location) []Summary {
+	return Records(records, loc, Year)
+}
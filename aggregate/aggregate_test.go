@@ -0,0 +1,299 @@
+package aggregate_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexhowarth/go-weatherlink"
+	"github.com/alexhowarth/go-weatherlink/aggregate"
+)
+
+func helperLoadRecords(t *testing.T) []weatherlink.HistoricRecord {
+	t.Helper()
+	b, err := ioutil.ReadFile(filepath.Join("..", "testdata", "historic.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var h weatherlink.HistoricResponse
+	if err := json.Unmarshal(b, &h); err != nil {
+		t.Fatal(err)
+	}
+	return h.Records()
+}
+
+func TestDaily(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	s := aggregate.Daily(helperLoadRecords(t), loc)
+
+	{
+		expect := 1
+		got := len(s)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	d := s[0]
+
+	{
+		expect := time.Date(2020, 6, 12, 0, 0, 0, 0, loc)
+		got := d.Start
+		if !got.Equal(expect) {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := 12
+		got := d.Records
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := 81.7
+		got := d.TempHi.Value
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := int64(1591984800)
+		got := d.TempHiAt.Unix()
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := 80.5
+		got := d.TempLo.Value
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := 81.142
+		got := d.TempMean.Value
+		if math.Abs(got-expect) > 0.01 {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := 22.0
+		got := d.WindGust.Value
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := 292.5
+		got := d.WindGustDir.Value
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := 11.0
+		got := d.WindRun.Value
+		if math.Abs(got-expect) > 0.001 {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := 0.0
+		got := d.Rainfall.Value
+		if !d.Rainfall.Valid || got != expect {
+			t.Fatalf("Expected %v got %v", expect, d.Rainfall)
+		}
+	}
+}
+
+func TestGustDirection(t *testing.T) {
+	at := time.Date(2020, 6, 12, 12, 0, 0, 0, time.UTC)
+
+	// a Vantage archive gives a compass point, an ISS archive degrees
+	vantage := &weatherlink.VantageArchive{WindSpeedHi: weatherlink.NewFloat(20), WindDirOfHi: weatherlink.NewFloat(4)}
+	iss := &weatherlink.ISSArchive{WindSpeedHi: weatherlink.NewFloat(20), WindSpeedHiDir: weatherlink.NewFloat(90)}
+
+	for _, d := range []weatherlink.SensorData{vantage, iss} {
+		s := aggregate.Daily([]weatherlink.HistoricRecord{{Time: at, Data: d}}, time.UTC)
+		expect := weatherlink.NewFloat(90)
+		got := s[0].WindGustDir
+		if got != expect {
+			t.Fatalf("Expected %v got %v for %T", expect, got, d)
+		}
+	}
+
+	// a missing direction stays null
+	noDir := &weatherlink.VantageArchive{WindSpeedHi: weatherlink.NewFloat(20)}
+	{
+		s := aggregate.Daily([]weatherlink.HistoricRecord{{Time: at, Data: noDir}}, time.UTC)
+		got := s[0].WindGustDir
+		if got.Valid {
+			t.Fatalf("Expected null got %v", got)
+		}
+	}
+}
+
+func TestSensors(t *testing.T) {
+	start := time.Date(2020, 6, 12, 12, 0, 0, 0, time.UTC)
+
+	iss := func(lsid int, at time.Time, temp float64, rain float64) weatherlink.HistoricRecord {
+		d := &weatherlink.ISSArchive{TempAvg: weatherlink.NewFloat(temp), RainfallIn: weatherlink.NewFloat(rain)}
+		d.Ts = at.Unix()
+		return weatherlink.HistoricRecord{Lsid: lsid, SensorType: 45, DataStructureType: 11, Time: at, Data: d}
+	}
+	bar := func(at time.Time, hi float64, lo float64) weatherlink.HistoricRecord {
+		d := &weatherlink.BarometerArchive{
+			BarSeaLevel: weatherlink.NewFloat((hi + lo) / 2),
+			BarHi:       weatherlink.NewFloat(hi),
+			BarLo:       weatherlink.NewFloat(lo),
+		}
+		d.Ts = at.Unix()
+		return weatherlink.HistoricRecord{Lsid: 3, SensorType: weatherlink.SensorTypeBarometer, DataStructureType: 13, Time: at, Data: d}
+	}
+
+	// three intervals of a primary ISS, a second ISS and a barometer
+	var records []weatherlink.HistoricRecord
+	for i := 0; i < 3; i++ {
+		at := start.Add(time.Duration(i) * 15 * time.Minute)
+		records = append(records,
+			iss(1, at, 70, 0.01),
+			iss(2, at, 90, 0.05),
+			bar(at, 30.1+float64(i)/10, 29.9-float64(i)/10),
+		)
+	}
+
+	s := aggregate.Daily(records, time.UTC)
+
+	{
+		expect := 3
+		got := s[0].Records
+		if len(s) != 1 || got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := 70.0
+		got := s[0].TempMean.Value
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := 0.03
+		got := s[0].Rainfall.Value
+		if math.Abs(got-expect) > 1e-9 {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := 30.3
+		got := s[0].BarHi.Value
+		if math.Abs(got-expect) > 1e-9 {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := 29.7
+		got := s[0].BarLo.Value
+		if math.Abs(got-expect) > 1e-9 {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	// the second ISS can be chosen instead
+	a := aggregate.New(time.UTC, aggregate.Day)
+	a.Outdoor = 2
+	for _, r := range records {
+		a.Add(r)
+	}
+	{
+		expect := 90.0
+		got := a.Summaries()[0].TempMean.Value
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
+
+func TestDayBoundary(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	record := func(t time.Time, rain float64) weatherlink.HistoricRecord {
+		d := &weatherlink.VantageArchive{RainfallIn: weatherlink.NewFloat(rain)}
+		d.Ts = t.Unix()
+		return weatherlink.HistoricRecord{Time: t, Data: d}
+	}
+
+	// the record stamped at midnight closes the interval ending that day
+	records := []weatherlink.HistoricRecord{
+		record(time.Date(2020, 6, 12, 23, 55, 0, 0, loc), 0.01),
+		record(time.Date(2020, 6, 13, 0, 0, 0, 0, loc), 0.02),
+		record(time.Date(2020, 6, 13, 0, 5, 0, 0, loc), 0.04),
+		{Time: time.Date(2020, 6, 13, 0, 10, 0, 0, loc), Data: &weatherlink.UnknownData{}},
+	}
+
+	s := aggregate.Daily(records, loc)
+
+	{
+		expect := 2
+		got := len(s)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := 0.03
+		got := s[0].Rainfall.Value
+		if math.Abs(got-expect) > 1e-9 {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := 1
+		got := s[1].Records
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	m := aggregate.Monthly(records, loc)
+
+	{
+		expect := 3
+		got := m[0].Records
+		if len(m) != 1 || got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := time.Date(2020, 7, 1, 0, 0, 0, 0, loc)
+		got := m[0].End
+		if !got.Equal(expect) {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
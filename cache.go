@@ -0,0 +1,278 @@
+package weatherlink
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultStationsTTL       = time.Hour
+	defaultSensorsTTL        = time.Hour
+	defaultNodesTTL          = time.Hour
+	defaultSensorCatalogTTL  = 24 * time.Hour
+	defaultSensorActivityTTL = time.Minute
+	defaultCurrentTTL        = time.Minute
+	defaultHistoricTTL       = time.Minute
+
+	// historicSettle is how long after its end a historic window is taken to be complete.
+	// Archive records can reach the API some time after they are recorded.
+	historicSettle = time.Hour
+)
+
+// Cache stores response bodies. Set it on Config to cache successful responses.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the value stored under key if it has not expired
+	Get(key string) ([]byte, bool)
+	// Set stores value under key until expires (forever if expires is zero)
+	Set(key string, value []byte, expires time.Time)
+}
+
+// CacheTTL controls how long each endpoint's responses are cached. A zero duration uses the
+// default and a negative duration disables caching of that endpoint. Historic windows that
+// ended over an hour ago never change, so they are cached without expiry unless Historic is
+// negative.
+type CacheTTL struct {
+	Stations       time.Duration // default 1h
+	Sensors        time.Duration // default 1h
+	Nodes          time.Duration // default 1h
+	SensorCatalog  time.Duration // default 24h
+	SensorActivity time.Duration // default 1m
+	Current        time.Duration // default 1m
+	Historic       time.Duration // windows that are not yet complete (default 1m)
+}
+
+// expires returns the expiry of a response to the request for rawurl with query q, and
+// false if it should not be cached. A zero time means the response never expires.
+func (c CacheTTL) expires(rawurl string, q url.Values, now time.Time) (time.Time, bool) {
+	ttl := func(d time.Duration, def time.Duration) (time.Time, bool) {
+		if d < 0 {
+			return time.Time{}, false
+		}
+		if d == 0 {
+			d = def
+		}
+		return now.Add(d), true
+	}
+	switch {
+	case strings.HasPrefix(rawurl, "/stations"):
+		return ttl(c.Stations, defaultStationsTTL)
+	case strings.HasPrefix(rawurl, "/sensors"):
+		return ttl(c.Sensors, defaultSensorsTTL)
+	case strings.HasPrefix(rawurl, "/nodes"):
+		return ttl(c.Nodes, defaultNodesTTL)
+	case strings.HasPrefix(rawurl, sensorCatalogPath):
+		return ttl(c.SensorCatalog, defaultSensorCatalogTTL)
+	case strings.HasPrefix(rawurl, "/sensor-activity"):
+		return ttl(c.SensorActivity, defaultSensorActivityTTL)
+	case strings.HasPrefix(rawurl, "/current"):
+		return ttl(c.Current, defaultCurrentTTL)
+	case strings.HasPrefix(rawurl, "/historic"):
+		if c.Historic < 0 {
+			return time.Time{}, false
+		}
+		end, err := strconv.ParseInt(q.Get("end-timestamp"), 10, 64)
+		if err == nil && now.Sub(time.Unix(end, 0)) > historicSettle {
+			return time.Time{}, true
+		}
+		return ttl(c.Historic, defaultHistoricTTL)
+	}
+	return time.Time{}, false
+}
+
+// cacheKey identifies a request by its base URL, path and parameters. The timestamp and
+// signature change with every request so they are left out.
+func (w *Client) cacheKey(u *url.URL, params SignatureParams) string {
+	p := make(map[string]string, len(params))
+	for k, v := range params {
+		p[k] = v
+	}
+	for k, v := range u.Query() {
+		p[k] = v[0]
+	}
+	delete(p, tParam)
+	delete(p, sigParam)
+
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf strings.Builder
+	buf.WriteString(w.baseURL.String())
+	buf.WriteString(u.Path)
+	for _, k := range keys {
+		buf.WriteString("&")
+		buf.WriteString(url.QueryEscape(k))
+		buf.WriteString("=")
+		buf.WriteString(url.QueryEscape(p[k]))
+	}
+	return buf.String()
+}
+
+// cached wraps a cached body in a response
+func cached(body []byte) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+	}
+}
+
+// MemoryCache is an in-memory Cache holding up to a fixed number of entries. The least
+// recently used entry is evicted to make room.
+type MemoryCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache returns a MemoryCache holding up to size entries
+func NewMemoryCache(size int) *MemoryCache {
+	if size < 1 {
+		panic("Size must be positive.")
+	}
+	return &MemoryCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Get returns the value stored under key if it has not expired
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*memoryEntry)
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		c.lru.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return e.value, true
+}
+
+// Set stores value under key until expires (forever if expires is zero)
+func (c *MemoryCache) Set(key string, value []byte, expires time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*memoryEntry)
+		e.value, e.expires = value, expires
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&memoryEntry{key: key, value: value, expires: expires})
+	for c.lru.Len() > c.size {
+		el := c.lru.Back()
+		c.lru.Remove(el)
+		delete(c.entries, el.Value.(*memoryEntry).key)
+	}
+}
+
+// Len returns the number of entries in the cache, including any that have expired but
+// have not been looked up since
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// DiskCache is a Cache storing one file per entry in a directory, so it is shared by
+// processes and survives restarts. Failures to read or write are treated as misses.
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache returns a DiskCache storing entries in dir, which is created if needed
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// file returns the path of the file holding key
+func (c *DiskCache) file(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+// Get returns the value stored under key if it has not expired. Each file starts with a line
+// holding the expiry in Unix nanoseconds (0 for none).
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	b, err := ioutil.ReadFile(c.file(key))
+	if err != nil {
+		return nil, false
+	}
+	i := bytes.IndexByte(b, '\n')
+	if i < 0 {
+		return nil, false
+	}
+	expires, err := strconv.ParseInt(string(b[:i]), 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	if expires != 0 && time.Now().UnixNano() > expires {
+		os.Remove(c.file(key))
+		return nil, false
+	}
+	return b[i+1:], true
+}
+
+// Set stores value under key until expires (forever if expires is zero)
+func (c *DiskCache) Set(key string, value []byte, expires time.Time) {
+	var ns int64
+	if !expires.IsZero() {
+		ns = expires.UnixNano()
+	}
+	f, err := ioutil.TempFile(c.dir, ".tmp-")
+	if err != nil {
+		return
+	}
+	_, err = f.WriteString(strconv.FormatInt(ns, 10) + "\n")
+	if err == nil {
+		_, err = f.Write(value)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return
+	}
+	// rename so a concurrent Get never sees a partial file
+	if err := os.Rename(f.Name(), c.file(key)); err != nil {
+		os.Remove(f.Name())
+	}
+}
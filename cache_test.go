@@ -0,0 +1,205 @@
+package weatherlink_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexhowarth/go-weatherlink"
+)
+
+func TestMemoryCache(t *testing.T) {
+	c := weatherlink.NewMemoryCache(2)
+
+	c.Set("a", []byte("1"), time.Time{})
+	c.Set("b", []byte("2"), time.Time{})
+	c.Get("a")
+	c.Set("c", []byte("3"), time.Time{})
+
+	// b was used least recently
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("Expected b to be evicted")
+	}
+	{
+		expect := "1"
+		got, _ := c.Get("a")
+		if string(got) != expect {
+			t.Fatalf("Expected %v got %v", expect, string(got))
+		}
+	}
+
+	c.Set("d", []byte("4"), time.Now().Add(-time.Second))
+	if _, ok := c.Get("d"); ok {
+		t.Fatal("Expected d to have expired")
+	}
+	{
+		expect := 1
+		got := c.Len()
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "weatherlink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c, err := weatherlink.NewDiskCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Set("a", []byte("{\n}"), time.Time{})
+	c.Set("b", []byte("2"), time.Now().Add(-time.Second))
+
+	{
+		expect := "{\n}"
+		got, ok := c.Get("a")
+		if !ok || string(got) != expect {
+			t.Fatalf("Expected %v got %v", expect, string(got))
+		}
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("Expected b to have expired")
+	}
+	if _, ok := c.Get("c"); ok {
+		t.Fatal("Expected c to be missing")
+	}
+
+	// a second cache on the same directory sees the entry
+	c2, err := weatherlink.NewDiskCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c2.Get("a"); !ok {
+		t.Fatal("Expected a to be shared")
+	}
+}
+
+func TestClientCache(t *testing.T) {
+
+	calls := map[string]int{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[strings.Split(r.URL.Path, "/")[1]]++
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/stations"):
+			w.Write(helperLoadBytes(t, "stations.json"))
+		case strings.HasPrefix(r.URL.Path, "/current"):
+			w.Write(helperLoadBytes(t, "current.json"))
+		case strings.HasPrefix(r.URL.Path, "/historic"):
+			w.Write(helperLoadBytes(t, "historic.json"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	conf := &weatherlink.Config{
+		Key:      "mykey",
+		Secret:   "mysecret",
+		BaseURL:  ts.URL,
+		Cache:    weatherlink.NewMemoryCache(10),
+		CacheTTL: weatherlink.CacheTTL{Current: -1},
+	}
+
+	wl := conf.NewClient()
+
+	for i := 0; i < 3; i++ {
+		s, err := wl.Stations([]int{2970})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect := 2970
+		got := s.Stations[0].StationID
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+		if _, err := wl.Current(2970); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// a different station is a different key
+	if _, err := wl.Stations([]int{2971}); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Unix(1591981200, 0)
+	for i := 0; i < 2; i++ {
+		if _, err := wl.Historic(2970, start, start.Add(time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// a window ending now is not complete, but is still cached for the default TTL
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		if _, err := wl.Historic(2970, now.Add(-time.Hour), now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	{
+		expect := 2
+		got := calls["stations"]
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := 3
+		got := calls["current"]
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := 2
+		got := calls["historic"]
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
+
+func TestClientCacheHistoricDisabled(t *testing.T) {
+
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write(helperLoadBytes(t, "historic.json"))
+	}))
+	defer ts.Close()
+
+	conf := &weatherlink.Config{
+		Key:      "mykey",
+		Secret:   "mysecret",
+		BaseURL:  ts.URL,
+		Cache:    weatherlink.NewMemoryCache(10),
+		CacheTTL: weatherlink.CacheTTL{Historic: -1},
+	}
+
+	wl := conf.NewClient()
+
+	// a settled window is not cached either
+	end := time.Now().Add(-48 * time.Hour)
+	for i := 0; i < 3; i++ {
+		if _, err := wl.Historic(2970, end.Add(-time.Hour), end); err != nil {
+			t.Fatal(err)
+		}
+	}
+	{
+		expect := 3
+		got := calls
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package weatherlink
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// SensorCatalog represents data from the /sensor-catalog endpoint, describing every type of sensor
+// and the data structures it reports
+type SensorCatalog struct {
+	SensorTypes []CatalogSensorType `json:"sensor_types"`
+}
+
+// CatalogSensorType describes one type of sensor
+type CatalogSensorType struct {
+	SensorType     int                    `json:"sensor_type"`
+	Manufacturer   string                 `json:"manufacturer"`
+	ProductName    string                 `json:"product_name"`
+	ProductNumber  string                 `json:"product_number"`
+	Category       string                 `json:"category"`
+	DataStructures []CatalogDataStructure `json:"data_structures"`
+}
+
+// CatalogDataStructure describes the fields of one data structure type reported by a sensor type
+type CatalogDataStructure struct {
+	DataStructureType int                     `json:"data_structure_type"`
+	Description       string                  `json:"description"`
+	Fields            map[string]CatalogField `json:"data_structure"`
+}
+
+// CatalogField describes one field of a data structure
+type CatalogField struct {
+	Type        string `json:"type"`
+	Units       string `json:"units"`
+	Description string `json:"description"`
+}
+
+// SensorType returns the catalogue entry for a sensor type
+func (c SensorCatalog) SensorType(sensorType int) (CatalogSensorType, bool) {
+	for _, st := range c.SensorTypes {
+		if st.SensorType == sensorType {
+			return st, true
+		}
+	}
+	return CatalogSensorType{}, false
+}
+
+// DataStructure returns the data structure for a sensor type and data structure type
+func (c SensorCatalog) DataStructure(sensorType int, dataStructureType int) (CatalogDataStructure, bool) {
+	st, ok := c.SensorType(sensorType)
+	if !ok {
+		return CatalogDataStructure{}, false
+	}
+	return st.DataStructure(dataStructureType)
+}
+
+// Field returns the description of a named field (e.g. "temp_out") in a data structure
+func (c SensorCatalog) Field(sensorType int, dataStructureType int, name string) (CatalogField, bool) {
+	ds, ok := c.DataStructure(sensorType, dataStructureType)
+	if !ok {
+		return CatalogField{}, false
+	}
+	f, ok := ds.Fields[name]
+	return f, ok
+}
+
+// DataStructure returns one of the data structures reported by this sensor type
+func (st CatalogSensorType) DataStructure(dataStructureType int) (CatalogDataStructure, bool) {
+	for _, ds := range st.DataStructures {
+		if ds.DataStructureType == dataStructureType {
+			return ds, true
+		}
+	}
+	return CatalogDataStructure{}, false
+}
+
+// SensorCatalog gets the catalogue of all types of sensors
+func (w *Client) SensorCatalog() (sc SensorCatalog, err error) {
+	return w.SensorCatalogContext(context.Background())
+}
+
+// SensorCatalogContext is like SensorCatalog but uses ctx for the request
+func (w *Client) SensorCatalogContext(ctx context.Context) (sc SensorCatalog, err error) {
+
+	resp, err := w.get(ctx, sensorCatalogPath, nil)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err = newAPIError("SensorCatalog", resp, nil, nil)
+		return
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&sc)
+	if err != nil {
+		return
+	}
+
+	return sc, nil
+}
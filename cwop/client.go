@@ -0,0 +1,84 @@
+package cwop
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// DefaultServer is the CWOP APRS-IS server used when Client.Server is empty
+const DefaultServer = "cwop.aprs.net:14580"
+
+// Software and Version identify this package to the APRS-IS server at login
+const (
+	Software = "go-weatherlink"
+	Version  = "1.0"
+)
+
+// Client submits packets to an APRS-IS server
+type Client struct {
+	Server   string        // host:port (default DefaultServer)
+	Callsign string        // callsign or CWOP id, e.g. EW1234
+	Passcode string        // APRS-IS passcode, -1 for CWOP ids without one
+	Timeout  time.Duration // limit on the whole submission (default 30s)
+}
+
+// Send logs in and sends the packets. CWOP asks that a station sends no more than once every
+// five minutes.
+func (c *Client) Send(ctx context.Context, packets ...string) error {
+	server := c.Server
+	if server == "" {
+		server = DefaultServer
+	}
+	passcode := c.Passcode
+	if passcode == "" {
+		passcode = "-1"
+	}
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	r := bufio.NewReader(conn)
+
+	// the server greets with a comment line
+	if _, err := r.ReadString('\n'); err != nil {
+		return fmt.Errorf("cwop: reading server banner: %v", err)
+	}
+
+	login := fmt.Sprintf("user %s pass %s vers %s %s\r\n", strings.ToUpper(c.Callsign), passcode, Software, Version)
+	if _, err := conn.Write([]byte(login)); err != nil {
+		return err
+	}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("cwop: reading login response: %v", err)
+		}
+		if strings.HasPrefix(line, "# logresp") {
+			break
+		}
+	}
+
+	for _, p := range packets {
+		if _, err := conn.Write([]byte(p + "\r\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
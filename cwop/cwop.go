@@ -0,0 +1,176 @@
+// Package cwop formats WeatherLink current conditions as APRS weather packets and submits them
+// to an APRS-IS server for the Citizen Weather Observer Program.
+//
+// A packet carries the station's position and its wind direction, speed and gust (mph),
+// temperature (°F), rain in the last hour, the last 24 hours and since midnight (hundredths
+// of an inch), humidity (%) and barometer (tenths of mb). Values the station does not
+// report are sent as dots, as the APRS specification allows.
+package cwop
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/alexhowarth/go-weatherlink"
+	"github.com/alexhowarth/go-weatherlink/units"
+)
+
+// Weather is the weather reported in a packet, in the units of the WeatherLink API
+type Weather struct {
+	Time         time.Time
+	WindDir      weatherlink.Float // degrees
+	WindSpeed    weatherlink.Float // mph, sustained
+	WindGust     weatherlink.Float // mph
+	Temp         weatherlink.Float // °F
+	Rain1h       weatherlink.Float // in
+	Rain24h      weatherlink.Float // in
+	RainMidnight weatherlink.Float // in
+	Hum          weatherlink.Float // %
+	Bar          weatherlink.Float // inHg, sea level
+}
+
+// first returns the first valid value
+func first(values ...weatherlink.Float) weatherlink.Float {
+	for _, v := range values {
+		if v.Valid {
+			return v
+		}
+	}
+	return weatherlink.Float{}
+}
+
+// set fills f from the first valid value if it is missing
+func set(f *weatherlink.Float, values ...weatherlink.Float) {
+	if !f.Valid {
+		*f = first(values...)
+	}
+}
+
+// FromCurrent collects the weather from current conditions. Where several sensors report a
+// value the first one is used. Records of other types than VantageCurrent, ISSCurrent and
+// BarometerCurrent are ignored.
+func FromCurrent(cr weatherlink.CurrentResponse) Weather {
+	var w Weather
+	for _, s := range cr.Sensors {
+		for _, data := range s.Data {
+			switch d := data.(type) {
+			case *weatherlink.VantageCurrent:
+				set(&w.WindDir, d.WindDir)
+				set(&w.WindSpeed, d.WindSpeed10MinAvg, d.WindSpeed)
+				set(&w.Temp, d.TempOut)
+				set(&w.RainMidnight, d.RainDayIn)
+				set(&w.Hum, d.HumOut)
+				set(&w.Bar, d.Bar)
+			case *weatherlink.ISSCurrent:
+				set(&w.WindDir, d.WindDirScalarAvgLast2Min, d.WindDirLast)
+				set(&w.WindSpeed, d.WindSpeedAvgLast2Min, d.WindSpeedLast)
+				set(&w.WindGust, d.WindSpeedHiLast10Min)
+				set(&w.Temp, d.Temp)
+				set(&w.Rain1h, d.RainfallLast60MinIn)
+				set(&w.Rain24h, d.RainfallLast24HrIn)
+				set(&w.RainMidnight, d.RainfallDailyIn)
+				set(&w.Hum, d.Hum)
+			case *weatherlink.BarometerCurrent:
+				set(&w.Bar, d.BarSeaLevel)
+			default:
+				continue
+			}
+			if t := data.Time(); t.After(w.Time) {
+				w.Time = t
+			}
+		}
+	}
+	return w
+}
+
+// Packet returns the APRS packet of callsign for the current conditions of station
+func Packet(callsign string, station weatherlink.Station, cr weatherlink.CurrentResponse) string {
+	return FromCurrent(cr).Format(callsign, station.Latitude, station.Longitude)
+}
+
+// Format returns the APRS packet of callsign at latitude and longitude (decimal degrees)
+func (w Weather) Format(callsign string, latitude float64, longitude float64) string {
+	var buf strings.Builder
+	buf.WriteString(strings.ToUpper(callsign))
+	buf.WriteString(">APRS,TCPIP*:")
+	if w.Time.IsZero() {
+		buf.WriteString("!")
+	} else {
+		buf.WriteString("@")
+		buf.WriteString(w.Time.UTC().Format("021504"))
+		buf.WriteString("z")
+	}
+	buf.WriteString(position(latitude, 2, "N", "S"))
+	buf.WriteString("/")
+	buf.WriteString(position(longitude, 3, "E", "W"))
+	buf.WriteString("_")
+
+	dir := w.WindDir
+	if dir.Valid && math.Round(dir.Value) == 0 {
+		// 0 means calm or unknown in APRS, north is 360
+		dir.Value = 360
+	}
+	buf.WriteString(field("", dir, 1, 3))
+	buf.WriteString(field("/", w.WindSpeed, 1, 3))
+	buf.WriteString(field("g", w.WindGust, 1, 3))
+	buf.WriteString(field("t", w.Temp, 1, 3))
+	buf.WriteString(field("r", w.Rain1h, 100, 3))
+	buf.WriteString(field("p", w.Rain24h, 100, 3))
+	buf.WriteString(field("P", w.RainMidnight, 100, 3))
+
+	hum := w.Hum
+	if hum.Valid {
+		// humidity is 1 to 100%, with 100% written as 00
+		switch r := math.Round(hum.Value); {
+		case r >= 100:
+			hum.Value = 0
+		case r < 1:
+			hum.Value = 1
+		}
+	}
+	buf.WriteString(field("h", hum, 1, 2))
+
+	bar := weatherlink.Float{}
+	if w.Bar.Valid {
+		if mb, err := units.ConvertValue(w.Bar.Value, units.InHg, units.HPa); err == nil {
+			bar = weatherlink.NewFloat(mb)
+		}
+	}
+	buf.WriteString(field("b", bar, 10, 5))
+	return buf.String()
+}
+
+// field formats a value multiplied by scale as a zero padded integer of width digits, clamped
+// to fit, or dots if it is missing
+func field(prefix string, v weatherlink.Float, scale float64, width int) string {
+	if !v.Valid {
+		return prefix + strings.Repeat(".", width)
+	}
+	n := int(math.Round(v.Value * scale))
+	if hi := int(math.Pow10(width)) - 1; n > hi {
+		n = hi
+	}
+	if n < 0 {
+		// the sign takes one of the digits
+		if lo := -(int(math.Pow10(width-1)) - 1); n < lo {
+			n = lo
+		}
+		return prefix + fmt.Sprintf("-%0*d", width-1, -n)
+	}
+	return prefix + fmt.Sprintf("%0*d", width, n)
+}
+
+// position formats decimal degrees as degrees and minutes to two decimal places
+func position(deg float64, width int, pos string, neg string) string {
+	hemi := pos
+	if deg < 0 {
+		hemi = neg
+		deg = -deg
+	}
+	hundredths := int(math.Round(deg * 60 * 100))
+	d := hundredths / 6000
+	m := hundredths % 6000
+	return fmt.Sprintf("%0*d%02d.%02d%s", width, d, m/100, m%100, hemi)
+}
@@ -0,0 +1,184 @@
+package cwop_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexhowarth/go-weatherlink"
+	"github.com/alexhowarth/go-weatherlink/cwop"
+)
+
+func helperLoad(t *testing.T, name string, v interface{}) {
+	t.Helper()
+	b, err := ioutil.ReadFile(filepath.Join("..", "testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPacketVantage(t *testing.T) {
+	var sr weatherlink.StationsResponse
+	helperLoad(t, "stations.json", &sr)
+	var cr weatherlink.CurrentResponse
+	helperLoad(t, "current.json", &cr)
+
+	expect := "EW1234>APRS,TCPIP*:@111650z4042.10N/07402.19W_216/017g...t076r...p...P001h81b10142"
+	got := cwop.Packet("ew1234", sr.Stations[0], cr)
+	if got != expect {
+		t.Fatalf("Expected %v got %v", expect, got)
+	}
+}
+
+func TestPacketWeatherLinkLive(t *testing.T) {
+	var cr weatherlink.CurrentResponse
+	helperLoad(t, "current-wll.json", &cr)
+
+	w := cwop.FromCurrent(cr)
+
+	{
+		expect := 30.008
+		got := w.Bar.Value
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	expect := "_346/002g008t063r...p...P003h01b10162"
+	got := w.Format("EW1234", -33.8688, 151.2093)
+	if !strings.HasSuffix(got, expect) {
+		t.Fatalf("Expected suffix %v got %v", expect, got)
+	}
+	if !strings.Contains(got, "3352.13S/15112.56E_") {
+		t.Fatalf("Expected southern and eastern position in %v", got)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	w := cwop.Weather{
+		WindDir:   weatherlink.NewFloat(0.2),
+		WindSpeed: weatherlink.NewFloat(0),
+		Temp:      weatherlink.NewFloat(-5.4),
+		Rain1h:    weatherlink.NewFloat(0.123),
+		Hum:       weatherlink.NewFloat(100),
+	}
+
+	// without a time the packet has no timestamp
+	expect := "EW1234>APRS,TCPIP*:!0000.00N/00000.00E_360/000g...t-05r012p...P...h00b....."
+	got := w.Format("EW1234", 0, 0)
+	if got != expect {
+		t.Fatalf("Expected %v got %v", expect, got)
+	}
+}
+
+func TestFormatHumidity(t *testing.T) {
+	for _, c := range []struct {
+		hum    float64
+		expect string
+	}{
+		{100, "h00"},
+		{99.6, "h00"},
+		{45.4, "h45"},
+		{0.4, "h01"},
+		{0, "h01"},
+	} {
+		w := cwop.Weather{Hum: weatherlink.NewFloat(c.hum)}
+		got := w.Format("EW1234", 0, 0)
+		if !strings.Contains(got, c.expect+"b") {
+			t.Fatalf("Expected %v for %v%% got %v", c.expect, c.hum, got)
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	lines := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte("# aprsc 2.1.10\r\n"))
+		r := bufio.NewReader(conn)
+		var got []string
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				break
+			}
+			line = strings.TrimRight(line, "\r\n")
+			got = append(got, line)
+			if strings.HasPrefix(line, "user ") {
+				conn.Write([]byte("# logresp EW1234 unverified, server CWOP-1\r\n"))
+			}
+		}
+		lines <- got
+	}()
+
+	c := &cwop.Client{
+		Server:   ln.Addr().String(),
+		Callsign: "ew1234",
+		Timeout:  5 * time.Second,
+	}
+	packet := "EW1234>APRS,TCPIP*:!0000.00N/00000.00E_360/000g...t-05r012p...P...h00b....."
+	if err := c.Send(context.Background(), packet); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	select {
+	case got = <-lines:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the server")
+	}
+
+	{
+		expect := 2
+		if len(got) != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := "user EW1234 pass -1 vers go-weatherlink 1.0"
+		if got[0] != expect {
+			t.Fatalf("Expected %v got %v", expect, got[0])
+		}
+	}
+
+	{
+		expect := packet
+		if got[1] != expect {
+			t.Fatalf("Expected %v got %v", expect, got[1])
+		}
+	}
+}
+
+func TestSendRefused(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	c := &cwop.Client{Server: addr, Callsign: "EW1234", Timeout: time.Second}
+	if err := c.Send(context.Background(), "x"); err == nil {
+		t.Fatal("Expected an error")
+	}
+}
@@ -0,0 +1,128 @@
+// Package derive calculates derived meteorological values from WeatherLink measurements.
+//
+// Functions take and return values in the units the WeatherLink API uses: temperatures in °F,
+// relative humidity in percent, wind speed in mph, pressure in inHg, elevation in feet and
+// solar radiation in W/m².
+package derive
+
+import "math"
+
+func toC(f float64) float64 { return (f - 32) * 5 / 9 }
+func toF(c float64) float64 { return c*9/5 + 32 }
+
+const hPaPerInHg = 33.8639
+const msPerMph = 0.44704
+
+// Magnus coefficients (Alduchov and Eskridge 1996)
+const (
+	magnusA = 17.625
+	magnusB = 243.04
+)
+
+// SaturationVapourPressure returns the saturation vapour pressure in inHg at a temperature
+func SaturationVapourPressure(temp float64) float64 {
+	t := toC(temp)
+	return 6.1094 * math.Exp(magnusA*t/(magnusB+t)) / hPaPerInHg
+}
+
+// VapourPressure returns the partial pressure of water vapour in inHg
+func VapourPressure(temp float64, hum float64) float64 {
+	return SaturationVapourPressure(temp) * hum / 100
+}
+
+// DewPoint returns the dew point
+func DewPoint(temp float64, hum float64) float64 {
+	if hum <= 0 {
+		return math.NaN()
+	}
+	t := toC(temp)
+	g := math.Log(hum/100) + magnusA*t/(magnusB+t)
+	return toF(magnusB * g / (magnusA - g))
+}
+
+// HeatIndex returns the NWS heat index. The index is defined for temperatures of at least
+// 80°F; below that the temperature is returned. Where the simple Steadman formula averages
+// below 80°F it is used, otherwise the Rothfusz regression with its low and high humidity
+// adjustments.
+func HeatIndex(temp float64, hum float64) float64 {
+	if temp < 80 {
+		return temp
+	}
+	simple := 0.5 * (temp + 61 + (temp-68)*1.2 + hum*0.094)
+	if (simple+temp)/2 < 80 {
+		return simple
+	}
+
+	t, r := temp, hum
+	hi := -42.379 + 2.04901523*t + 10.14333127*r - 0.22475541*t*r - 0.00683783*t*t -
+		0.05481717*r*r + 0.00122874*t*t*r + 0.00085282*t*r*r - 0.00000199*t*t*r*r
+
+	switch {
+	case r < 13 && t >= 80 && t <= 112:
+		hi -= (13 - r) / 4 * math.Sqrt((17-math.Abs(t-95))/17)
+	case r > 85 && t >= 80 && t <= 87:
+		hi += (r - 85) / 10 * (87 - t) / 5
+	}
+	return hi
+}
+
+// WindChill returns the NWS (2001) wind chill. The formula is defined for temperatures at or
+// below 50°F and wind speeds of at least 3 mph; outside that range the temperature is returned.
+func WindChill(temp float64, wind float64) float64 {
+	if temp > 50 || wind < 3 {
+		return temp
+	}
+	v := math.Pow(wind, 0.16)
+	wc := 35.74 + 0.6215*temp - 35.75*v + 0.4275*temp*v
+	return math.Min(wc, temp)
+}
+
+// THW returns the temperature-humidity-wind index: the heat index lowered by the cooling
+// effect of the wind, as reported by Davis consoles
+func THW(temp float64, hum float64, wind float64) float64 {
+	return HeatIndex(temp, hum) - (temp - WindChill(temp, wind))
+}
+
+// THSW returns the temperature-humidity-sun-wind index: THW raised by the heating effect of
+// the sun. The solar term is Steadman's radiation term with a tenth of the incoming radiation
+// taken as absorbed.
+func THSW(temp float64, hum float64, wind float64, solarRad float64) float64 {
+	q := 0.1 * math.Max(solarRad, 0)
+	ws := wind * msPerMph
+	return THW(temp, hum, wind) + 0.7*q/(ws+10)*9/5
+}
+
+// WetBulb returns the wet bulb temperature at sea level pressure (Stull 2011), valid for
+// humidity between 5% and 99% and temperatures between -20°C and 50°C
+func WetBulb(temp float64, hum float64) float64 {
+	t, r := toC(temp), hum
+	tw := t*math.Atan(0.151977*math.Sqrt(r+8.313659)) +
+		math.Atan(t+r) - math.Atan(r-1.676331) +
+		0.00391838*math.Pow(r, 1.5)*math.Atan(0.023101*r) - 4.686035
+	return toF(tw)
+}
+
+// ApparentTemperature returns the Australian Bureau of Meteorology apparent temperature
+// (Steadman 1994, without radiation)
+func ApparentTemperature(temp float64, hum float64, wind float64) float64 {
+	e := VapourPressure(temp, hum) * hPaPerInHg
+	return toF(toC(temp) + 0.33*e - 0.70*wind*msPerMph - 4.00)
+}
+
+// AbsoluteHumidity returns the mass of water vapour in g/m³
+func AbsoluteHumidity(temp float64, hum float64) float64 {
+	e := VapourPressure(temp, hum) * hPaPerInHg * 100 // Pa
+	return 1000 * e / (461.5 * (toC(temp) + 273.15))
+}
+
+// CloudBase returns the estimated height in feet above the station of the base of cumulus clouds
+func CloudBase(temp float64, dewPoint float64) float64 {
+	return math.Max(temp-dewPoint, 0) / 4.4 * 1000
+}
+
+// SeaLevelPressure reduces the absolute (station) pressure to sea level using the standard
+// atmosphere, as the barometer reading on Davis consoles is
+func SeaLevelPressure(absPress float64, elevation float64) float64 {
+	h := elevation * 0.3048
+	return absPress * math.Pow(1-0.0065*h/(288.15+0.0065*h), -5.257)
+}
@@ -0,0 +1,122 @@
+package derive
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+// archive records from the Davis console, used to validate the calculations
+type archive struct {
+	Ts           int64   `json:"ts"`
+	TempOut      float64 `json:"temp_out"`
+	HumOut       float64 `json:"hum_out"`
+	Bar          float64 `json:"bar"`
+	AbsPress     float64 `json:"abs_press"`
+	DewPointOut  float64 `json:"dew_point_out"`
+	HeatIndexOut float64 `json:"heat_index_out"`
+}
+
+// elevation of the station in testdata/stations.json
+const elevation = 20.01288
+
+func helperLoadArchive(t *testing.T) []archive {
+	b, err := ioutil.ReadFile(filepath.Join("..", "testdata", "historic.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hr struct {
+		Sensors []struct {
+			Data []archive `json:"data"`
+		} `json:"sensors"`
+	}
+	if err := json.Unmarshal(b, &hr); err != nil {
+		t.Fatal(err)
+	}
+	return hr.Sensors[0].Data
+}
+
+func helperWithin(t *testing.T, name string, expect float64, got float64, tolerance float64) {
+	t.Helper()
+	if math.Abs(expect-got) > tolerance {
+		t.Fatalf("%v: expected %v got %v", name, expect, got)
+	}
+}
+
+func TestArchive(t *testing.T) {
+
+	for _, r := range helperLoadArchive(t) {
+		helperWithin(t, "dew point", r.DewPointOut, DewPoint(r.TempOut, r.HumOut), 0.05)
+		helperWithin(t, "sea level pressure", r.Bar, SeaLevelPressure(r.AbsPress, elevation), 0.001)
+
+		// the console looks the heat index up in a table rather than using the NWS regression
+		helperWithin(t, "heat index", r.HeatIndexOut, HeatIndex(r.TempOut, r.HumOut), 1.1)
+	}
+}
+
+func TestWindChill(t *testing.T) {
+
+	// NWS wind chill chart
+	helperWithin(t, "wind chill", -19, WindChill(0, 15), 0.5)
+	helperWithin(t, "wind chill", 34, WindChill(40, 10), 0.5)
+	helperWithin(t, "wind chill", -12, WindChill(10, 30), 0.5)
+
+	// outside the range of the formula
+	helperWithin(t, "wind chill", 80.6, WindChill(80.6, 8), 0)
+	helperWithin(t, "wind chill", 30, WindChill(30, 2), 0)
+}
+
+func TestHeatIndex(t *testing.T) {
+
+	// NWS heat index chart
+	helperWithin(t, "heat index", 91, HeatIndex(86, 60), 0.5)
+	helperWithin(t, "heat index", 121, HeatIndex(96, 65), 0.5)
+	helperWithin(t, "heat index", 82, HeatIndex(80, 65), 0.5)
+
+	// outside the range of the formula
+	helperWithin(t, "heat index", 30, HeatIndex(30, 60), 0)
+	helperWithin(t, "heat index", 79.9, HeatIndex(79.9, 100), 0)
+}
+
+func TestTHW(t *testing.T) {
+
+	// warm and windy: no wind chill so THW is the heat index
+	helperWithin(t, "thw", HeatIndex(86, 60), THW(86, 60, 10), 0)
+
+	// cold: no heat index so THW is the wind chill
+	got := THW(30, 60, 20)
+	helperWithin(t, "thw", WindChill(30, 20), got, 0)
+	if got >= 30 {
+		t.Fatalf("Expected THW below the temperature got %v", got)
+	}
+
+	// the sun raises THSW above THW
+	if THSW(86, 60, 5, 800) <= THW(86, 60, 5) {
+		t.Fatalf("Expected THSW above THW")
+	}
+	helperWithin(t, "thsw", THW(86, 60, 5), THSW(86, 60, 5, 0), 0)
+}
+
+func TestWetBulb(t *testing.T) {
+
+	// Stull (2011): 20°C and 50% gives 13.7°C
+	helperWithin(t, "wet bulb", 13.7, toC(WetBulb(68, 50)), 0.1)
+}
+
+func TestHumidity(t *testing.T) {
+
+	// 20°C and 50%
+	helperWithin(t, "vapour pressure", 11.69, VapourPressure(68, 50)*hPaPerInHg, 0.05)
+	helperWithin(t, "absolute humidity", 8.64, AbsoluteHumidity(68, 50), 0.05)
+
+	// 25°C, 50% and calm
+	helperWithin(t, "apparent temperature", 26.2, toC(ApparentTemperature(77, 50, 0)), 0.1)
+}
+
+func TestCloudBase(t *testing.T) {
+
+	helperWithin(t, "cloud base", 5000, CloudBase(70, 48), 0.001)
+	helperWithin(t, "cloud base", 0, CloudBase(50, 50), 0)
+}
@@ -0,0 +1,80 @@
+package weatherlink
+
+import (
+	"math"
+
+	"github.com/alexhowarth/go-weatherlink/derive"
+)
+
+// Derived holds values calculated from the measurements of a current conditions record by Derive
+type Derived struct {
+	DewPoint         Float `json:"dew_point" unit:"degF"`
+	HeatIndex        Float `json:"heat_index" unit:"degF"`
+	WindChill        Float `json:"wind_chill" unit:"degF"`
+	ThwIndex         Float `json:"thw_index" unit:"degF"`
+	ThswIndex        Float `json:"thsw_index" unit:"degF"`
+	WetBulb          Float `json:"wet_bulb" unit:"degF"`
+	ApparentTemp     Float `json:"apparent_temp" unit:"degF"`
+	VapourPressure   Float `json:"vapour_pressure" unit:"inHg"`
+	AbsoluteHumidity Float `json:"absolute_humidity"` // g/m³
+	CloudBase        Float `json:"cloud_base" unit:"ft"`
+	SeaLevelPressure Float `json:"sea_level_pressure" unit:"inHg"`
+}
+
+// Derive fills in the Derived values of the outdoor and barometer records. elevation is the
+// station elevation in feet (Station.Elevation), used to reduce absolute pressure to sea level.
+func (c *CurrentResponse) Derive(elevation float64) {
+	for _, s := range c.Sensors {
+		for _, data := range s.Data {
+			switch d := data.(type) {
+			case *VantageCurrent:
+				d.Derived = deriveOutdoor(d.TempOut, d.HumOut, d.WindSpeed, d.SolarRad)
+			case *ISSCurrent:
+				d.Derived = deriveOutdoor(d.Temp, d.Hum, d.WindSpeedLast, d.SolarRad)
+			case *BarometerCurrent:
+				if d.BarAbsolute.Valid {
+					d.Derived = &Derived{
+						SeaLevelPressure: float(derive.SeaLevelPressure(d.BarAbsolute.Value, elevation)),
+					}
+				}
+			}
+		}
+	}
+}
+
+// deriveOutdoor calculates the values that the available measurements allow
+func deriveOutdoor(temp Float, hum Float, wind Float, solarRad Float) *Derived {
+	d := &Derived{}
+	if !temp.Valid {
+		return d
+	}
+	t := temp.Value
+	if hum.Valid && hum.Value > 0 {
+		h := hum.Value
+		d.DewPoint = float(derive.DewPoint(t, h))
+		d.HeatIndex = float(derive.HeatIndex(t, h))
+		d.WetBulb = float(derive.WetBulb(t, h))
+		d.VapourPressure = float(derive.VapourPressure(t, h))
+		d.AbsoluteHumidity = float(derive.AbsoluteHumidity(t, h))
+		d.CloudBase = float(derive.CloudBase(t, d.DewPoint.Value))
+	}
+	if wind.Valid {
+		d.WindChill = float(derive.WindChill(t, wind.Value))
+	}
+	if hum.Valid && hum.Value > 0 && wind.Valid {
+		d.ThwIndex = float(derive.THW(t, hum.Value, wind.Value))
+		d.ApparentTemp = float(derive.ApparentTemperature(t, hum.Value, wind.Value))
+		if solarRad.Valid {
+			d.ThswIndex = float(derive.THSW(t, hum.Value, wind.Value, solarRad.Value))
+		}
+	}
+	return d
+}
+
+// float returns a Float that is missing if v is not a number
+func float(v float64) Float {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return Float{}
+	}
+	return NewFloat(v)
+}
@@ -0,0 +1,95 @@
+package weatherlink
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned when the API responds with a status other than 200 OK
+type APIError struct {
+	Endpoint   string // name of the endpoint method, e.g. "Stations"
+	StatusCode int
+	Status     string
+	Code       string // WeatherLink error code from the response body, if any
+	Message    string // WeatherLink error message from the response body, if any
+	Path       string
+	StationIDs []int
+	SensorIDs  []int
+	Body       []byte
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("Error making %v request. Got status: %v", e.Endpoint, e.Status)
+	switch {
+	case e.Code != "" && e.Message != "":
+		msg += fmt.Sprintf(" (code %v: %v)", e.Code, e.Message)
+	case e.Message != "":
+		msg += fmt.Sprintf(" (%v)", e.Message)
+	}
+	return msg
+}
+
+// newAPIError builds an APIError from a non-200 response, consuming its body
+func newAPIError(endpoint string, resp *http.Response, stations []int, sensors []int) *APIError {
+	e := &APIError{
+		Endpoint:   endpoint,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		StationIDs: stations,
+		SensorIDs:  sensors,
+	}
+	if e.Status == "" {
+		e.Status = fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	if resp.Request != nil && resp.Request.URL != nil {
+		e.Path = resp.Request.URL.Path
+	}
+	if resp.Body == nil {
+		return e
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return e
+	}
+	e.Body = body
+
+	var b struct {
+		Code    json.RawMessage `json:"code"`
+		Message string          `json:"message"`
+	}
+	if json.Unmarshal(body, &b) == nil {
+		e.Code = strings.Trim(string(b.Code), `"`)
+		e.Message = b.Message
+	}
+	return e
+}
+
+// IsUnauthorized reports whether err is an APIError with status 401 (bad key or signature)
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether err is an APIError with status 403
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsNotFound reports whether err is an APIError with status 404
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsRateLimited reports whether err is an APIError with status 429
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+func hasStatus(err error, status int) bool {
+	var e *APIError
+	return errors.As(err, &e) && e.StatusCode == status
+}
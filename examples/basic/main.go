@@ -41,9 +41,16 @@ func main() {
 		for _, sensor := range cuRes.Sensors {
 			// for each sensor, get some data
 			for _, data := range sensor.Data {
-				fmt.Printf("Wind Direction: %v\n", data.WindDir)
-				fmt.Printf("Wind Speed: %v\n", data.WindSpeed)
-				fmt.Printf("Last updated: %v\n", time.Unix(int64(data.Ts), 0))
+				// the type of data depends on the sensor
+				switch d := data.(type) {
+				case *weatherlink.VantageCurrent:
+					fmt.Printf("Wind Direction: %v\n", d.WindDir)
+					fmt.Printf("Wind Speed: %v\n", d.WindSpeed)
+				case *weatherlink.ISSCurrent:
+					fmt.Printf("Wind Direction: %v\n", d.WindDirLast)
+					fmt.Printf("Wind Speed: %v\n", d.WindSpeedLast)
+				}
+				fmt.Printf("Last updated: %v\n", time.Unix(data.Timestamp(), 0))
 			}
 		}
 
@@ -62,9 +69,15 @@ func main() {
 		for _, sensor := range hiRes.Sensors {
 			// for each sensor, get some data
 			for _, data := range sensor.Data {
-				fmt.Printf("Date: %v\n", time.Unix(int64(data.Ts), 0))
-				fmt.Printf("Prevailing wind Direction: %v\n", data.WindDirOfPrevail)
-				fmt.Printf("Wind Speed high: %v\n", data.WindSpeedHi)
+				fmt.Printf("Date: %v\n", time.Unix(data.Timestamp(), 0))
+				switch d := data.(type) {
+				case *weatherlink.VantageArchive:
+					fmt.Printf("Prevailing wind Direction: %v\n", d.WindDirOfPrevail)
+					fmt.Printf("Wind Speed high: %v\n", d.WindSpeedHi)
+				case *weatherlink.ISSArchive:
+					fmt.Printf("Prevailing wind Direction: %v\n", d.WindDirOfPrevail)
+					fmt.Printf("Wind Speed high: %v\n", d.WindSpeedHi)
+				}
 			}
 		}
 
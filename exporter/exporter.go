@@ -0,0 +1,316 @@
+// Package exporter serves the current conditions of WeatherLink stations as Prometheus metrics.
+//
+// An Exporter polls Current for each station and serves the latest values in the Prometheus
+// text exposition format. Every measurement of a record becomes a gauge named after its JSON
+// field (weatherlink_temp_out, weatherlink_wind_speed_last, ...) labelled with station_id,
+// station_name, lsid and sensor_type. Measurements the API reports as null are left out.
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexhowarth/go-weatherlink"
+)
+
+const namespace = "weatherlink"
+
+// DefaultInterval is how often stations are polled when Exporter.Interval is zero
+const DefaultInterval = time.Minute
+
+// latencyBuckets are the upper bounds in seconds of the request latency histogram
+var latencyBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Exporter polls stations and serves their metrics. It is an http.Handler for /metrics.
+type Exporter struct {
+	Client   *weatherlink.Client
+	Stations []int
+	Interval time.Duration // time between polls (default DefaultInterval)
+
+	mu       sync.Mutex
+	names    map[int]string
+	current  map[int]weatherlink.CurrentResponse
+	up       map[int]bool
+	polled   map[int]time.Time
+	requests map[int]int
+	errors   map[errorKey]int
+	latency  map[int]*histogram
+}
+
+type errorKey struct {
+	station int
+	status  string
+}
+
+type histogram struct {
+	counts []int // per bucket, not cumulative
+	count  int
+	sum    float64
+}
+
+// New returns an Exporter for stations
+func New(c *weatherlink.Client, stations []int) *Exporter {
+	return &Exporter{
+		Client:   c,
+		Stations: stations,
+		names:    make(map[int]string),
+		current:  make(map[int]weatherlink.CurrentResponse),
+		up:       make(map[int]bool),
+		polled:   make(map[int]time.Time),
+		requests: make(map[int]int),
+		errors:   make(map[errorKey]int),
+		latency:  make(map[int]*histogram),
+	}
+}
+
+// Run polls the stations every Interval until ctx is done
+func (e *Exporter) Run(ctx context.Context) error {
+	interval := e.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		e.Poll(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Poll gets the current conditions of every station once
+func (e *Exporter) Poll(ctx context.Context) {
+	e.pollNames(ctx)
+	for _, station := range e.Stations {
+		start := time.Now()
+		cr, err := e.Client.CurrentContext(ctx, station)
+		took := time.Now().Sub(start)
+
+		e.mu.Lock()
+		e.requests[station]++
+		h, ok := e.latency[station]
+		if !ok {
+			h = &histogram{counts: make([]int, len(latencyBuckets))}
+			e.latency[station] = h
+		}
+		h.observe(took.Seconds())
+		if err != nil {
+			e.errors[errorKey{station, status(err)}]++
+			e.up[station] = false
+		} else {
+			e.current[station] = cr
+			e.up[station] = true
+			e.polled[station] = time.Now()
+		}
+		e.mu.Unlock()
+	}
+}
+
+// pollNames looks up the names of stations not yet known. A failure is tried again next poll.
+func (e *Exporter) pollNames(ctx context.Context) {
+	e.mu.Lock()
+	var missing []int
+	for _, s := range e.Stations {
+		if _, ok := e.names[s]; !ok {
+			missing = append(missing, s)
+		}
+	}
+	e.mu.Unlock()
+	if len(missing) == 0 {
+		return
+	}
+	sr, err := e.Client.StationsContext(ctx, missing)
+	if err != nil {
+		return
+	}
+	e.mu.Lock()
+	for _, s := range sr.Stations {
+		e.names[s.StationID] = s.StationName
+	}
+	e.mu.Unlock()
+}
+
+// status returns the label of a failed request: the HTTP status of an API error, or "error"
+func status(err error) string {
+	var apiErr *weatherlink.APIError
+	if errors.As(err, &apiErr) {
+		return strconv.Itoa(apiErr.StatusCode)
+	}
+	return "error"
+}
+
+func (h *histogram) observe(v float64) {
+	for i, b := range latencyBuckets {
+		if v <= b {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	e.WriteTo(w)
+}
+
+// sample is one line of a metric family. Histograms have a suffix (_bucket, _sum or _count).
+type sample struct {
+	suffix string
+	labels string
+	value  float64
+}
+
+// family is a metric with its samples
+type family struct {
+	help    string
+	typ     string
+	samples []sample
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format
+func (e *Exporter) WriteTo(w io.Writer) (int64, error) {
+	e.mu.Lock()
+	families := e.collect()
+	e.mu.Unlock()
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf strings.Builder
+	for _, name := range names {
+		f := families[name]
+		fmt.Fprintf(&buf, "# HELP %s %s\n", name, f.help)
+		fmt.Fprintf(&buf, "# TYPE %s %s\n", name, f.typ)
+		for _, s := range f.samples {
+			fmt.Fprintf(&buf, "%s%s%s %s\n", name, s.suffix, s.labels, strconv.FormatFloat(s.value, 'g', -1, 64))
+		}
+	}
+	n, err := io.WriteString(w, buf.String())
+	return int64(n), err
+}
+
+// collect builds the metric families from the latest state
+func (e *Exporter) collect() map[string]*family {
+	families := make(map[string]*family)
+	add := func(name string, help string, typ string, s sample) {
+		f, ok := families[name]
+		if !ok {
+			f = &family{help: help, typ: typ}
+			families[name] = f
+		}
+		f.samples = append(f.samples, s)
+	}
+	gauge := func(name string, help string, labels string, v float64) {
+		add(name, help, "gauge", sample{labels: labels, value: v})
+	}
+	counter := func(name string, help string, labels string, v float64) {
+		add(name, help, "counter", sample{labels: labels, value: v})
+	}
+
+	now := time.Now()
+	for _, station := range e.Stations {
+		sl := labels("station_id", strconv.Itoa(station))
+
+		up := 0.0
+		if e.up[station] {
+			up = 1
+		}
+		gauge(namespace+"_up", "Whether the last request for current conditions succeeded.", sl, up)
+		counter(namespace+"_api_requests_total", "Requests made for current conditions.", sl, float64(e.requests[station]))
+		if t, ok := e.polled[station]; ok {
+			gauge(namespace+"_last_success_timestamp_seconds", "Time of the last successful request for current conditions.", sl, float64(t.UnixNano())/1e9)
+		}
+
+		if h, ok := e.latency[station]; ok {
+			name := namespace + "_api_request_duration_seconds"
+			help := "Latency of requests for current conditions."
+			cum := 0
+			for i, b := range latencyBuckets {
+				cum += h.counts[i]
+				le := labels("station_id", strconv.Itoa(station), "le", strconv.FormatFloat(b, 'g', -1, 64))
+				add(name, help, "histogram", sample{suffix: "_bucket", labels: le, value: float64(cum)})
+			}
+			le := labels("station_id", strconv.Itoa(station), "le", "+Inf")
+			add(name, help, "histogram", sample{suffix: "_bucket", labels: le, value: float64(h.count)})
+			add(name, help, "histogram", sample{suffix: "_sum", labels: sl, value: h.sum})
+			add(name, help, "histogram", sample{suffix: "_count", labels: sl, value: float64(h.count)})
+		}
+
+		cr, ok := e.current[station]
+		if !ok {
+			continue
+		}
+		for _, s := range cr.Sensors {
+			for _, d := range s.Data {
+				rl := labels(
+					"station_id", strconv.Itoa(station),
+					"station_name", e.names[station],
+					"lsid", strconv.Itoa(s.Lsid),
+					"sensor_type", strconv.Itoa(s.SensorType),
+				)
+				gauge(namespace+"_data_age_seconds", "Age of the record at the time of the scrape.", rl, now.Sub(d.Time()).Seconds())
+				for _, m := range weatherlink.Measurements(d) {
+					if m.Value.Valid {
+						gauge(namespace+"_"+m.Name, "Current value of "+m.Name+" reported by the sensor.", rl, m.Value.Value)
+					}
+				}
+			}
+		}
+	}
+
+	errKeys := make([]errorKey, 0, len(e.errors))
+	for k := range e.errors {
+		errKeys = append(errKeys, k)
+	}
+	sort.Slice(errKeys, func(a, b int) bool {
+		if errKeys[a].station != errKeys[b].station {
+			return errKeys[a].station < errKeys[b].station
+		}
+		return errKeys[a].status < errKeys[b].status
+	})
+	for _, k := range errKeys {
+		counter(namespace+"_api_errors_total", "Failed requests for current conditions by HTTP status.",
+			labels("station_id", strconv.Itoa(k.station), "status", k.status), float64(e.errors[k]))
+	}
+	return families
+}
+
+// labels formats label pairs, escaping the values
+func labels(kv ...string) string {
+	var buf strings.Builder
+	buf.WriteString("{")
+	for i := 0; i+1 < len(kv); i += 2 {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString(kv[i])
+		buf.WriteString(`="`)
+		buf.WriteString(escape(kv[i+1]))
+		buf.WriteString(`"`)
+	}
+	buf.WriteString("}")
+	return buf.String()
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
@@ -0,0 +1,103 @@
+package exporter_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexhowarth/go-weatherlink"
+	"github.com/alexhowarth/go-weatherlink/exporter"
+	"github.com/alexhowarth/go-weatherlink/weatherlinktest"
+)
+
+func helperServer(t *testing.T) *weatherlinktest.Server {
+	t.Helper()
+	b, err := ioutil.ReadFile(filepath.Join("..", "testdata", "current.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cr weatherlink.CurrentResponse
+	if err := json.Unmarshal(b, &cr); err != nil {
+		t.Fatal(err)
+	}
+	s := weatherlinktest.NewServer("mykey", "mysecret")
+	s.AddStation(weatherlink.Station{StationID: 2970, StationName: `Home "garden"`})
+	s.SetCurrent(2970, cr)
+	return s
+}
+
+func helperScrape(t *testing.T, e *exporter.Exporter) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	{
+		expect := "text/plain; version=0.0.4; charset=utf-8"
+		got := rec.Header().Get("Content-Type")
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	return rec.Body.String()
+}
+
+func TestExporter(t *testing.T) {
+	s := helperServer(t)
+	defer s.Close()
+
+	e := exporter.New(s.Config().NewClient(), []int{2970})
+	e.Poll(context.Background())
+
+	body := helperScrape(t, e)
+
+	for _, expect := range []string{
+		"# TYPE weatherlink_temp_out gauge\n",
+		`weatherlink_temp_out{station_id="2970",station_name="Home \"garden\"",lsid="12822",sensor_type="37"} 75.6` + "\n",
+		`weatherlink_bar{station_id="2970",station_name="Home \"garden\"",lsid="12822",sensor_type="37"} 29.95` + "\n",
+		`weatherlink_up{station_id="2970"} 1` + "\n",
+		`weatherlink_api_requests_total{station_id="2970"} 1` + "\n",
+		"# TYPE weatherlink_api_request_duration_seconds histogram\n",
+		`weatherlink_api_request_duration_seconds_bucket{station_id="2970",le="+Inf"} 1` + "\n",
+		`weatherlink_api_request_duration_seconds_count{station_id="2970"} 1` + "\n",
+		"# TYPE weatherlink_data_age_seconds gauge\n",
+	} {
+		if !strings.Contains(body, expect) {
+			t.Fatalf("Expected %q in\n%v", expect, body)
+		}
+	}
+
+	// null values are left out
+	if strings.Contains(body, "weatherlink_temp_extra_1{") {
+		t.Fatalf("Expected no temp_extra_1 in\n%v", body)
+	}
+}
+
+func TestExporterErrors(t *testing.T) {
+	s := helperServer(t)
+	defer s.Close()
+
+	e := exporter.New(s.Config().NewClient(), []int{2970})
+	e.Poll(context.Background())
+
+	s.FailNext(1, http.StatusServiceUnavailable)
+	e.Poll(context.Background())
+
+	body := helperScrape(t, e)
+
+	for _, expect := range []string{
+		`weatherlink_up{station_id="2970"} 0` + "\n",
+		`weatherlink_api_requests_total{station_id="2970"} 2` + "\n",
+		"# TYPE weatherlink_api_errors_total counter\n",
+		`weatherlink_api_errors_total{station_id="2970",status="503"} 1` + "\n",
+		// the last values are still served
+		`weatherlink_temp_out{station_id="2970",station_name="Home \"garden\"",lsid="12822",sensor_type="37"} 75.6` + "\n",
+	} {
+		if !strings.Contains(body, expect) {
+			t.Fatalf("Expected %q in\n%v", expect, body)
+		}
+	}
+}
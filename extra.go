@@ -0,0 +1,279 @@
+package weatherlink
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// knownFields caches the JSON keys of each struct type
+var knownFields sync.Map // reflect.Type -> map[string]bool
+
+// extraFields returns the keys of the JSON object b that v (a pointer to a struct) has no field
+// for, and the fields of v that b has no key for. Either is nil if there are none.
+func extraFields(b []byte, v interface{}) (extra map[string]json.RawMessage, absent map[string]bool, err error) {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, nil, err
+	}
+	known := fieldNames(reflect.TypeOf(v).Elem())
+	for k := range known {
+		if _, ok := m[k]; !ok {
+			if absent == nil {
+				absent = make(map[string]bool)
+			}
+			absent[k] = true
+		}
+	}
+	for k := range m {
+		if known[k] {
+			delete(m, k)
+		}
+	}
+	if len(m) == 0 {
+		m = nil
+	}
+	return m, absent, nil
+}
+
+// marshalFields encodes v (a pointer to a struct type without a MarshalJSON method) with the
+// extra fields added, leaving out the fields in absent that still have their zero value, so a
+// decoded value is written back as it was received
+func marshalFields(v interface{}, extra map[string]json.RawMessage, absent map[string]bool) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || (len(extra) == 0 && len(absent) == 0) {
+		return b, err
+	}
+
+	var zero map[string]json.RawMessage
+	if len(absent) > 0 {
+		z, err := json.Marshal(reflect.New(reflect.TypeOf(v).Elem()).Interface())
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(z, &zero); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	n := 0
+	write := func(k string, raw json.RawMessage) {
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(raw)
+		n++
+	}
+
+	// copy the fields in order, as a map would sort them
+	buf.WriteByte('{')
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		k := t.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, err
+		}
+		if absent[k] && bytes.Equal(raw, zero[k]) {
+			continue
+		}
+		write(k, raw)
+	}
+
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		write(k, extra[k])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// fieldNames returns the JSON keys of a struct type, including those of embedded structs
+func fieldNames(t reflect.Type) map[string]bool {
+	if names, ok := knownFields.Load(t); ok {
+		return names.(map[string]bool)
+	}
+	names := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		if f.Anonymous && tag == "" && f.Type.Kind() == reflect.Struct {
+			for k := range fieldNames(f.Type) {
+				names[k] = true
+			}
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = f.Name
+		}
+		names[name] = true
+	}
+	knownFields.Store(t, names)
+	return names
+}
+
+// UnmarshalJSON keeps fields not known to this package in Extra
+func (s *Station) UnmarshalJSON(b []byte) (err error) {
+	type station Station
+	if err = json.Unmarshal(b, (*station)(s)); err != nil {
+		return
+	}
+	s.Extra, s.absent, err = extraFields(b, s)
+	return
+}
+
+// MarshalJSON writes the station as it was received, including the fields in Extra
+func (s *Station) MarshalJSON() ([]byte, error) {
+	type station Station
+	return marshalFields((*station)(s), s.Extra, s.absent)
+}
+
+// UnmarshalJSON keeps fields not known to this package in Extra
+func (s *Sensor) UnmarshalJSON(b []byte) (err error) {
+	type sensor Sensor
+	if err = json.Unmarshal(b, (*sensor)(s)); err != nil {
+		return
+	}
+	s.Extra, s.absent, err = extraFields(b, s)
+	return
+}
+
+// MarshalJSON writes the sensor as it was received, including the fields in Extra
+func (s *Sensor) MarshalJSON() ([]byte, error) {
+	type sensor Sensor
+	return marshalFields((*sensor)(s), s.Extra, s.absent)
+}
+
+// MarshalJSON writes the record as it was received, including the fields in Extra
+func (d *VantageCurrent) MarshalJSON() ([]byte, error) {
+	type record VantageCurrent
+	return marshalFields((*record)(d), d.Extra, d.absent)
+}
+
+// MarshalJSON writes the record as it was received, including the fields in Extra
+func (d *VantageArchive) MarshalJSON() ([]byte, error) {
+	type record VantageArchive
+	return marshalFields((*record)(d), d.Extra, d.absent)
+}
+
+// MarshalJSON writes the record as it was received, including the fields in Extra
+func (d *ISSCurrent) MarshalJSON() ([]byte, error) {
+	type record ISSCurrent
+	return marshalFields((*record)(d), d.Extra, d.absent)
+}
+
+// MarshalJSON writes the record as it was received, including the fields in Extra
+func (d *ISSArchive) MarshalJSON() ([]byte, error) {
+	type record ISSArchive
+	return marshalFields((*record)(d), d.Extra, d.absent)
+}
+
+// MarshalJSON writes the record as it was received, including the fields in Extra
+func (d *SoilLeafCurrent) MarshalJSON() ([]byte, error) {
+	type record SoilLeafCurrent
+	return marshalFields((*record)(d), d.Extra, d.absent)
+}
+
+// MarshalJSON writes the record as it was received, including the fields in Extra
+func (d *SoilLeafArchive) MarshalJSON() ([]byte, error) {
+	type record SoilLeafArchive
+	return marshalFields((*record)(d), d.Extra, d.absent)
+}
+
+// MarshalJSON writes the record as it was received, including the fields in Extra
+func (d *BarometerCurrent) MarshalJSON() ([]byte, error) {
+	type record BarometerCurrent
+	return marshalFields((*record)(d), d.Extra, d.absent)
+}
+
+// MarshalJSON writes the record as it was received, including the fields in Extra
+func (d *BarometerArchive) MarshalJSON() ([]byte, error) {
+	type record BarometerArchive
+	return marshalFields((*record)(d), d.Extra, d.absent)
+}
+
+// MarshalJSON writes the record as it was received, including the fields in Extra
+func (d *TempHumCurrent) MarshalJSON() ([]byte, error) {
+	type record TempHumCurrent
+	return marshalFields((*record)(d), d.Extra, d.absent)
+}
+
+// MarshalJSON writes the record as it was received, including the fields in Extra
+func (d *TempHumArchive) MarshalJSON() ([]byte, error) {
+	type record TempHumArchive
+	return marshalFields((*record)(d), d.Extra, d.absent)
+}
+
+// MarshalJSON writes the record as it was received, including the fields in Extra
+func (d *AirLinkCurrent) MarshalJSON() ([]byte, error) {
+	type record AirLinkCurrent
+	return marshalFields((*record)(d), d.Extra, d.absent)
+}
+
+// MarshalJSON writes the record as it was received, including the fields in Extra
+func (d *AirLinkArchive) MarshalJSON() ([]byte, error) {
+	type record AirLinkArchive
+	return marshalFields((*record)(d), d.Extra, d.absent)
+}
+
+// UnmarshalJSON keeps the response as received in Raw
+func (r *StationsResponse) UnmarshalJSON(b []byte) error {
+	type response StationsResponse
+	if err := json.Unmarshal(b, (*response)(r)); err != nil {
+		return err
+	}
+	r.Raw = append(json.RawMessage(nil), b...)
+	return nil
+}
+
+// UnmarshalJSON keeps the response as received in Raw
+func (r *SensorsResponse) UnmarshalJSON(b []byte) error {
+	type response SensorsResponse
+	if err := json.Unmarshal(b, (*response)(r)); err != nil {
+		return err
+	}
+	r.Raw = append(json.RawMessage(nil), b...)
+	return nil
+}
+
+// UnmarshalJSON keeps the response as received in Raw
+func (r *CurrentResponse) UnmarshalJSON(b []byte) error {
+	type response CurrentResponse
+	if err := json.Unmarshal(b, (*response)(r)); err != nil {
+		return err
+	}
+	r.Raw = append(json.RawMessage(nil), b...)
+	return nil
+}
+
+// UnmarshalJSON keeps the response as received in Raw
+func (r *HistoricResponse) UnmarshalJSON(b []byte) error {
+	type response HistoricResponse
+	if err := json.Unmarshal(b, (*response)(r)); err != nil {
+		return err
+	}
+	r.Raw = append(json.RawMessage(nil), b...)
+	return nil
+}
@@ -0,0 +1,138 @@
+package weatherlink
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestStationsExtra(t *testing.T) {
+
+	in := `{"stations":[{"station_id":1,"station_name":"Foo","new_field":"x"}],"generated_at":2,"new_top":true}`
+
+	var sr StationsResponse
+	if err := json.Unmarshal([]byte(in), &sr); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(sr.Raw) != in {
+		t.Fatalf("Expected %v got %v", in, string(sr.Raw))
+	}
+	{
+		expect := `"x"`
+		got := string(sr.Stations[0].Extra["new_field"])
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := 1
+		got := len(sr.Stations[0].Extra)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
+
+func TestSensorDataExtra(t *testing.T) {
+
+	in := `{"station_id":1,"sensors":[{"lsid":2,"sensor_type":37,"data_structure_type":2,"data":[` +
+		`{"ts":3,"temp_out":70.1,"new_reading":4.5},{"ts":4,"temp_out":70.2}]}]}`
+
+	var cr CurrentResponse
+	if err := json.Unmarshal([]byte(in), &cr); err != nil {
+		t.Fatal(err)
+	}
+
+	d := cr.Sensors[0].Data[0].(*VantageCurrent)
+	{
+		expect := "4.5"
+		got := string(d.Extra["new_reading"])
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := NewFloat(70.1)
+		got := d.TempOut
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	if extra := cr.Sensors[0].Data[1].(*VantageCurrent).Extra; extra != nil {
+		t.Fatalf("Expected no extra fields got %v", extra)
+	}
+}
+
+func TestExtraRoundTrip(t *testing.T) {
+
+	// a Vantage archive record has no solar_rad_avg field, and leaves out most of those it has
+	in := `{"station_id":1,"sensors":[{"lsid":2,"data":[{"ts":3,"temp_out":70.1,"bar":null,"solar_rad_avg":250}],` +
+		`"sensor_type":37,"data_structure_type":4}],"generated_at":5}`
+
+	var hr HistoricResponse
+	if err := json.Unmarshal([]byte(in), &hr); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(hr.Sensors[0].Data[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	{
+		expect := `{"ts":3,"temp_out":70.1,"bar":null,"solar_rad_avg":250}`
+		got := string(b)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	// a value set after decoding is written even if it was missing
+	hr.Sensors[0].Data[0].(*VantageArchive).TempIn = NewFloat(68)
+	b, err = json.Marshal(hr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	{
+		expect := `{"ts":3,"temp_out":70.1,"temp_in":68,"bar":null,"solar_rad_avg":250}`
+		got := string(b)
+		if !strings.Contains(got, expect) {
+			t.Fatalf("Expected %v in %v", expect, got)
+		}
+	}
+
+	sin := `{"station_id":1,"station_name":"Foo","new_field":"x"}`
+	var s Station
+	if err := json.Unmarshal([]byte(sin), &s); err != nil {
+		t.Fatal(err)
+	}
+	b, err = json.Marshal(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	{
+		expect := sin
+		got := string(b)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	nin := `{"lsid":2,"sensor_type":37,"tx_id":null,"new_field":[1,2]}`
+	var n Sensor
+	if err := json.Unmarshal([]byte(nin), &n); err != nil {
+		t.Fatal(err)
+	}
+	b, err = json.Marshal(&n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	{
+		expect := nin
+		got := string(b)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
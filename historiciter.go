@@ -0,0 +1,140 @@
+package weatherlink
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// HistoricRecord is one archive record flattened with the station and sensor it belongs to
+type HistoricRecord struct {
+	StationID         int
+	Lsid              int
+	SensorType        int
+	DataStructureType int
+	Time              time.Time
+	Data              SensorData
+}
+
+// Records returns the records of every sensor flattened and sorted by time
+func (h HistoricResponse) Records() []HistoricRecord {
+	var records []HistoricRecord
+	for _, s := range h.Sensors {
+		for _, d := range s.Data {
+			records = append(records, HistoricRecord{
+				StationID:         h.StationID,
+				Lsid:              s.Lsid,
+				SensorType:        s.SensorType,
+				DataStructureType: s.DataStructureType,
+				Time:              time.Unix(d.Timestamp(), 0),
+				Data:              d,
+			})
+		}
+	}
+	sort.SliceStable(records, func(a, b int) bool { return records[a].Time.Before(records[b].Time) })
+	return records
+}
+
+// HistoricIterator walks the historic records of a station one at a time. Records are fetched
+// lazily, one request of up to MaxHistoricSpan at a time, so memory use does not grow with the range.
+//
+//	it := wl.IterateHistoric(ctx, 123, start, end)
+//	defer it.Close()
+//	for it.Next() {
+//		r := it.Record()
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle error
+//	}
+type HistoricIterator struct {
+	w       *Client
+	ctx     context.Context
+	cancel  context.CancelFunc
+	station int
+	chunks  [][2]time.Time
+	buf     []HistoricRecord
+	rec     HistoricRecord
+	last    map[int]int64 // latest timestamp yielded per lsid
+	err     error
+}
+
+// IterateHistoric returns an iterator over the historic records of one station ID within a
+// given timerange. Records are yielded in timestamp order within each chunk of MaxHistoricSpan.
+func (w *Client) IterateHistoric(ctx context.Context, station int, start time.Time, end time.Time) *HistoricIterator {
+	ctx, cancel := context.WithCancel(ctx)
+	return &HistoricIterator{
+		w:       w,
+		ctx:     ctx,
+		cancel:  cancel,
+		station: station,
+		chunks:  historicChunks(start, end),
+		last:    make(map[int]int64),
+	}
+}
+
+// Next advances to the next record, fetching more data as needed. It returns false when there
+// are no more records or an error occurred.
+func (it *HistoricIterator) Next() bool {
+	for len(it.buf) == 0 {
+		if it.err != nil || len(it.chunks) == 0 {
+			return false
+		}
+		it.fetch()
+	}
+	it.rec = it.buf[0]
+	it.buf = it.buf[1:]
+	return true
+}
+
+// Record returns the current record
+func (it *HistoricIterator) Record() HistoricRecord {
+	return it.rec
+}
+
+// Err returns the first error encountered while fetching records
+func (it *HistoricIterator) Err() error {
+	return it.err
+}
+
+// Close stops the iterator, cancelling any request in progress
+func (it *HistoricIterator) Close() error {
+	it.cancel()
+	it.chunks = nil
+	it.buf = nil
+	return nil
+}
+
+// fetch requests the next chunk and fills the buffer with its records
+func (it *HistoricIterator) fetch() {
+	c := it.chunks[0]
+	it.chunks = it.chunks[1:]
+
+	hr, err := it.w.HistoricContext(it.ctx, it.station, c[0], c[1])
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	var buf []HistoricRecord
+	for _, s := range hr.Sensors {
+		for _, d := range s.Data {
+			// records on a chunk boundary are returned by both requests
+			if last, ok := it.last[s.Lsid]; ok && d.Timestamp() <= last {
+				continue
+			}
+			buf = append(buf, HistoricRecord{
+				StationID:         it.station,
+				Lsid:              s.Lsid,
+				SensorType:        s.SensorType,
+				DataStructureType: s.DataStructureType,
+				Time:              time.Unix(d.Timestamp(), 0),
+				Data:              d,
+			})
+		}
+	}
+	sort.SliceStable(buf, func(a, b int) bool { return buf[a].Data.Timestamp() < buf[b].Data.Timestamp() })
+	for _, r := range buf {
+		it.last[r.Lsid] = r.Data.Timestamp()
+	}
+	it.buf = buf
+}
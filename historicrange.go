@@ -0,0 +1,115 @@
+package weatherlink
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MaxHistoricSpan is the longest time range the API accepts in a single historic request
+const MaxHistoricSpan = 24 * time.Hour
+
+// HistoricRange gets historic data for one station ID over any time range. The range is split into
+// requests no longer than MaxHistoricSpan, fetched Config.HistoricConcurrency at a time, and the
+// records are merged per sensor in timestamp order with duplicates at chunk boundaries removed.
+// The merged response has no Raw.
+func (w *Client) HistoricRange(ctx context.Context, station int, start time.Time, end time.Time) (hr HistoricResponse, err error) {
+
+	chunks := historicChunks(start, end)
+	results := make([]HistoricResponse, len(chunks))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := w.Config.HistoricConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+	sem := make(chan struct{}, workers)
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	for i, c := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int, c [2]time.Time) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			r, e := w.HistoricContext(ctx, station, c[0], c[1])
+			if e != nil {
+				once.Do(func() {
+					err = e
+					cancel()
+				})
+				return
+			}
+			results[i] = r
+		}(i, c)
+	}
+	wg.Wait()
+
+	if err != nil {
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	return mergeHistoric(station, results), nil
+}
+
+// historicChunks splits start to end into consecutive ranges no longer than MaxHistoricSpan
+func historicChunks(start time.Time, end time.Time) [][2]time.Time {
+	var chunks [][2]time.Time
+	for s := start; s.Before(end); s = s.Add(MaxHistoricSpan) {
+		e := s.Add(MaxHistoricSpan)
+		if e.After(end) {
+			e = end
+		}
+		chunks = append(chunks, [2]time.Time{s, e})
+	}
+	return chunks
+}
+
+// mergeHistoric combines historic responses, keeping one sensor entry per lsid with its records
+// sorted by timestamp and records sharing a timestamp de-duplicated
+func mergeHistoric(station int, results []HistoricResponse) (hr HistoricResponse) {
+	hr.StationID = station
+	index := make(map[int]int)
+	for _, r := range results {
+		if r.GeneratedAt > hr.GeneratedAt {
+			hr.GeneratedAt = r.GeneratedAt
+		}
+		for _, s := range r.Sensors {
+			i, ok := index[s.Lsid]
+			if !ok {
+				index[s.Lsid] = len(hr.Sensors)
+				hr.Sensors = append(hr.Sensors, s)
+				continue
+			}
+			hr.Sensors[i].Data = append(hr.Sensors[i].Data, s.Data...)
+		}
+	}
+
+	for i := range hr.Sensors {
+		data := hr.Sensors[i].Data
+		sort.SliceStable(data, func(a, b int) bool { return data[a].Timestamp() < data[b].Timestamp() })
+		n := 0
+		for j := range data {
+			if n > 0 && data[j].Timestamp() == data[n-1].Timestamp() {
+				continue
+			}
+			data[n] = data[j]
+			n++
+		}
+		hr.Sensors[i].Data = data[:n]
+	}
+	return hr
+}
@@ -0,0 +1,201 @@
+// Package influx encodes WeatherLink records in the InfluxDB line protocol and writes them to
+// an InfluxDB v2 server.
+//
+// Each record becomes one line. The measurement is the sensor's category (e.g. iss, barometer)
+// when the sensor is known to the Encoder, and weatherlink otherwise. The line is tagged with
+// station, lsid, sensor_type and, for known sensors, product. Every measurement of the record
+// that is not null becomes a field, and the timestamp is the record's ts in nanoseconds.
+package influx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/alexhowarth/go-weatherlink"
+)
+
+// DefaultMeasurement is the measurement of records of sensors not known to the Encoder
+const DefaultMeasurement = "weatherlink"
+
+// Encoder writes records as line protocol
+type Encoder struct {
+	w       io.Writer
+	sensors map[int]weatherlink.Sensor
+}
+
+// NewEncoder returns an Encoder writing to w
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, sensors: make(map[int]weatherlink.Sensor)}
+}
+
+// SetSensors gives the Encoder the sensors from the /sensors endpoint, which name the
+// measurement and product of their records
+func (e *Encoder) SetSensors(sensors []weatherlink.Sensor) {
+	for _, s := range sensors {
+		e.sensors[s.Lsid] = s
+	}
+}
+
+// EncodeCurrent writes the records of a current conditions response
+func (e *Encoder) EncodeCurrent(cr weatherlink.CurrentResponse) error {
+	for _, s := range cr.Sensors {
+		for _, d := range s.Data {
+			r := weatherlink.HistoricRecord{
+				StationID:         cr.StationID,
+				Lsid:              s.Lsid,
+				SensorType:        s.SensorType,
+				DataStructureType: s.DataStructureType,
+				Time:              d.Time(),
+				Data:              d,
+			}
+			if err := e.Encode(r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// EncodeHistoric writes the records of a historic response
+func (e *Encoder) EncodeHistoric(hr weatherlink.HistoricResponse) error {
+	for _, r := range hr.Records() {
+		if err := e.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Encode writes one record. Records without any measurement are skipped, since a line
+// needs at least one field.
+func (e *Encoder) Encode(r weatherlink.HistoricRecord) error {
+	var fields []string
+	for _, m := range weatherlink.Measurements(r.Data) {
+		if m.Value.Valid {
+			fields = append(fields, escapeKey(m.Name)+"="+strconv.FormatFloat(m.Value.Value, 'g', -1, 64))
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	measurement := DefaultMeasurement
+	tags := []string{
+		"lsid=" + strconv.Itoa(r.Lsid),
+	}
+	if s, ok := e.sensors[r.Lsid]; ok {
+		if c := name(s.Category); c != "" {
+			measurement = c
+		}
+		if s.ProductName != "" {
+			tags = append(tags, "product="+escapeKey(s.ProductName))
+		}
+	}
+	tags = append(tags, "sensor_type="+strconv.Itoa(r.SensorType), "station="+strconv.Itoa(r.StationID))
+	// tags in key order, as InfluxDB prefers
+	sort.Strings(tags)
+
+	t := r.Time
+	if t.IsZero() && r.Data != nil {
+		t = r.Data.Time()
+	}
+
+	var buf strings.Builder
+	buf.WriteString(escapeMeasurement(measurement))
+	buf.WriteString(",")
+	buf.WriteString(strings.Join(tags, ","))
+	buf.WriteString(" ")
+	buf.WriteString(strings.Join(fields, ","))
+	buf.WriteString(" ")
+	buf.WriteString(strconv.FormatInt(t.UnixNano(), 10))
+	buf.WriteString("\n")
+	_, err := io.WriteString(e.w, buf.String())
+	return err
+}
+
+// name turns a sensor category into a measurement name: "Inside Temp/Hum" becomes inside_temp_hum
+func name(category string) string {
+	var buf strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(category) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if underscore && buf.Len() > 0 {
+				buf.WriteByte('_')
+			}
+			underscore = false
+			buf.WriteRune(r)
+			continue
+		}
+		underscore = true
+	}
+	return buf.String()
+}
+
+var measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+var keyEscaper = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+
+func escapeMeasurement(s string) string {
+	return measurementEscaper.Replace(s)
+}
+
+// escapeKey escapes a tag key, tag value or field key
+func escapeKey(s string) string {
+	return keyEscaper.Replace(s)
+}
+
+// Writer posts line protocol to the /api/v2/write endpoint of an InfluxDB v2 server
+type Writer struct {
+	URL    string // server location, e.g. http://localhost:8086
+	Token  string
+	Org    string
+	Bucket string
+	Client *http.Client // a client with a 30s timeout if nil
+}
+
+// Write posts lines to the server
+func (w *Writer) Write(ctx context.Context, lines []byte) error {
+	u, err := url.Parse(w.URL)
+	if err != nil {
+		return err
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/api/v2/write"
+	q := url.Values{}
+	q.Set("org", w.Org)
+	q.Set("bucket", w.Bucket)
+	q.Set("precision", "ns")
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(lines))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if w.Token != "" {
+		req.Header.Set("Authorization", "Token "+w.Token)
+	}
+
+	c := w.Client
+	if c == nil {
+		c = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("influx: write failed with status %v: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
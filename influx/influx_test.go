@@ -0,0 +1,180 @@
+package influx_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexhowarth/go-weatherlink"
+	"github.com/alexhowarth/go-weatherlink/influx"
+)
+
+func helperLoad(t *testing.T, name string, v interface{}) {
+	t.Helper()
+	b, err := ioutil.ReadFile(filepath.Join("..", "testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEncodeCurrent(t *testing.T) {
+	var cr weatherlink.CurrentResponse
+	helperLoad(t, "current.json", &cr)
+	var sr weatherlink.SensorsResponse
+	helperLoad(t, "sensors.json", &sr)
+
+	var buf bytes.Buffer
+	e := influx.NewEncoder(&buf)
+	e.SetSensors(sr.Sensors)
+	if err := e.EncodeCurrent(cr); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+
+	{
+		expect := 1
+		got := len(lines)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	line := lines[0]
+
+	{
+		expect := `iss,lsid=12822,product=Vantage\ Vue\,\ Wireless,sensor_type=37,station=2970 `
+		if !strings.HasPrefix(line, expect) {
+			t.Fatalf("Expected prefix %v got %v", expect, line)
+		}
+	}
+
+	{
+		expect := " 1591894200000000000"
+		if !strings.HasSuffix(line, expect) {
+			t.Fatalf("Expected suffix %v got %v", expect, line)
+		}
+	}
+
+	for _, expect := range []string{"temp_out=75.6", "bar=29.95", "hum_out=81", "rain_day_in=0.01"} {
+		if !strings.Contains(line, expect) {
+			t.Fatalf("Expected %v in %v", expect, line)
+		}
+	}
+
+	// null values are left out
+	if strings.Contains(line, "temp_extra_1=") {
+		t.Fatalf("Expected no temp_extra_1 in %v", line)
+	}
+}
+
+func TestEncodeHistoric(t *testing.T) {
+	var hr weatherlink.HistoricResponse
+	helperLoad(t, "historic.json", &hr)
+
+	var buf bytes.Buffer
+	e := influx.NewEncoder(&buf)
+	if err := e.EncodeHistoric(hr); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+
+	{
+		expect := 12
+		got := len(lines)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	// without sensor metadata the default measurement is used
+	{
+		expect := "weatherlink,lsid=12822,sensor_type=37,station=2970 "
+		if !strings.HasPrefix(lines[0], expect) {
+			t.Fatalf("Expected prefix %v got %v", expect, lines[0])
+		}
+	}
+
+	{
+		expect := " 1591981500000000000"
+		if !strings.HasSuffix(lines[0], expect) {
+			t.Fatalf("Expected suffix %v got %v", expect, lines[0])
+		}
+	}
+}
+
+func TestEncodeUnknown(t *testing.T) {
+	var buf bytes.Buffer
+	e := influx.NewEncoder(&buf)
+	e.SetSensors([]weatherlink.Sensor{{Lsid: 1, Category: "Inside Temp/Hum"}})
+
+	d := &weatherlink.TempHumCurrent{TempIn: weatherlink.NewFloat(70)}
+	d.Ts = 1
+	if err := e.Encode(weatherlink.HistoricRecord{StationID: 2, Lsid: 1, SensorType: 243, Data: d}); err != nil {
+		t.Fatal(err)
+	}
+	// records without any measurement are skipped
+	if err := e.Encode(weatherlink.HistoricRecord{StationID: 2, Lsid: 1, Data: &weatherlink.UnknownData{}}); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := "inside_temp_hum,lsid=1,sensor_type=243,station=2 temp_in=70 1000000000\n"
+	got := buf.String()
+	if got != expect {
+		t.Fatalf("Expected %q got %q", expect, got)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var body []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/write" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		q := r.URL.Query()
+		if q.Get("org") != "myorg" || q.Get("bucket") != "weather" || q.Get("precision") != "ns" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("Authorization") != "Token mytoken" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code":"unauthorized","message":"unauthorized access"}`))
+			return
+		}
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	w := &influx.Writer{URL: ts.URL, Token: "mytoken", Org: "myorg", Bucket: "weather"}
+
+	lines := []byte("weatherlink,lsid=1 temp=70 1000000000\n")
+	if err := w.Write(context.Background(), lines); err != nil {
+		t.Fatal(err)
+	}
+
+	{
+		expect := string(lines)
+		got := string(body)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	w.Token = "wrong"
+	err := w.Write(context.Background(), lines)
+	if err == nil || !strings.Contains(err.Error(), "unauthorized access") {
+		t.Fatalf("Expected unauthorized got %v", err)
+	}
+}
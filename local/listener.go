@@ -0,0 +1,165 @@
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alexhowarth/go-weatherlink"
+)
+
+const (
+	// DefaultBroadcastPort is the UDP port the gateway broadcasts real time data to
+	DefaultBroadcastPort = 22222
+
+	defaultBroadcastDuration = 20 * time.Minute
+	maxPacketSize            = 64 * 1024
+)
+
+// Packet is one real time broadcast of the gateway, sent every 2.5 seconds
+type Packet struct {
+	Did        string        `json:"did"` // device id of the gateway
+	Ts         int64         `json:"ts"`
+	Conditions []RealTimeISS `json:"conditions"`
+}
+
+// Time returns the time of the packet
+func (p Packet) Time() time.Time {
+	return time.Unix(p.Ts, 0)
+}
+
+// RealTimeISS is the wind and rain of an ISS in a real time broadcast. Rain is in clicks of
+// size RainSize.
+type RealTimeISS struct {
+	Header
+	WindSpeedLast             weatherlink.Float `json:"wind_speed_last" unit:"mph"`
+	WindDirLast               weatherlink.Float `json:"wind_dir_last"`
+	WindSpeedHiLast10Min      weatherlink.Float `json:"wind_speed_hi_last_10_min" unit:"mph"`
+	WindDirAtHiSpeedLast10Min weatherlink.Float `json:"wind_dir_at_hi_speed_last_10_min"`
+	RainSize                  int               `json:"rain_size"`
+	RainRateLast              weatherlink.Float `json:"rain_rate_last"` // clicks/h
+	Rain15Min                 weatherlink.Float `json:"rain_15_min"`
+	Rain60Min                 weatherlink.Float `json:"rain_60_min"`
+	Rain24Hr                  weatherlink.Float `json:"rain_24_hr"`
+	RainStorm                 weatherlink.Float `json:"rain_storm"`
+	RainStormStartAt          int64             `json:"rain_storm_start_at"`
+	RainfallDaily             weatherlink.Float `json:"rainfall_daily"`
+	RainfallMonthly           weatherlink.Float `json:"rainfall_monthly"`
+	RainfallYear              weatherlink.Float `json:"rainfall_year"`
+}
+
+// Listener receives the real time broadcasts of a gateway. It asks the gateway to broadcast and
+// renews the request before it runs out, for as long as it is listening.
+type Listener struct {
+	Client   *LiveClient
+	Address  string          // UDP address to listen on (default ":22222")
+	Duration time.Duration   // length of each broadcast request, renewed half way through (default 20m)
+	OnError  func(err error) // called with renewal and decoding errors, which do not stop the listener (optional)
+
+	mu   sync.Mutex
+	conn net.PacketConn
+}
+
+// NewListener returns a Listener for the gateway of c
+func NewListener(c *LiveClient) *Listener {
+	return &Listener{Client: c}
+}
+
+// LocalAddr returns the address the listener receives on, or nil if it is not listening
+func (l *Listener) LocalAddr() net.Addr {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		return nil
+	}
+	return l.conn.LocalAddr()
+}
+
+// Listen starts the broadcast and returns a channel of the packets received. The channel is
+// closed when ctx is done.
+func (l *Listener) Listen(ctx context.Context) (<-chan Packet, error) {
+	addr := l.Address
+	if addr == "" {
+		addr = net.JoinHostPort("", strconv.Itoa(DefaultBroadcastPort))
+	}
+	d := l.Duration
+	if d < time.Second {
+		d = defaultBroadcastDuration
+	}
+
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := l.Client.RealTimeContext(ctx, d); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	l.mu.Lock()
+	l.conn = conn
+	l.mu.Unlock()
+
+	ch := make(chan Packet)
+	ctx, cancel := context.WithCancel(ctx)
+	go l.renew(ctx, d)
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+	go func() {
+		defer cancel()
+		defer close(ch)
+		l.read(ctx, conn, ch)
+	}()
+	return ch, nil
+}
+
+// renew asks for the broadcast again half way through each request
+func (l *Listener) renew(ctx context.Context, d time.Duration) {
+	t := time.NewTicker(d / 2)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if _, err := l.Client.RealTimeContext(ctx, d); err != nil && ctx.Err() == nil {
+				l.error(err)
+			}
+		}
+	}
+}
+
+// read decodes packets from conn until it is closed
+func (l *Listener) read(ctx context.Context, conn net.PacketConn, ch chan<- Packet) {
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() == nil {
+				l.error(err)
+			}
+			return
+		}
+		var p Packet
+		if err := json.Unmarshal(buf[:n], &p); err != nil {
+			l.error(err)
+			continue
+		}
+		select {
+		case ch <- p:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (l *Listener) error(err error) {
+	if l.OnError != nil {
+		l.OnError(err)
+	}
+}
@@ -0,0 +1,127 @@
+package local_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alexhowarth/go-weatherlink/local"
+)
+
+const packet = `{"did":"001D0A700002","ts":1532031476,"conditions":[{"lsid":48308,"data_structure_type":1,"wind_speed_last":2,"wind_dir_last":270,"rain_size":2,"rain_rate_last":null,"rain_15_min":0,"rain_60_min":0,"rain_24_hr":3,"rain_storm":0,"rain_storm_start_at":0,"rainfall_daily":3,"rainfall_monthly":10,"rainfall_year":120,"wind_speed_hi_last_10_min":8,"wind_dir_at_hi_speed_last_10_min":260}]}`
+
+func TestListener(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{"data":{"broadcast_port":22222,"duration":1},"error":null}`))
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	l := local.NewListener(local.NewLiveClient(ts.URL))
+	l.Address = "127.0.0.1:0"
+	l.Duration = time.Second
+
+	var errs int32
+	l.OnError = func(err error) { atomic.AddInt32(&errs, 1) }
+
+	ch, err := l.Listen(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := net.Dial("udp", l.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	conn.Write([]byte("not json"))
+	conn.Write([]byte(packet))
+
+	var p local.Packet
+	select {
+	case p = <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for a packet")
+	}
+
+	{
+		expect := int64(1532031476)
+		got := p.Ts
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := 8.0
+		got := p.Conditions[0].WindSpeedHiLast10Min.Value
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := 3.0
+		got := p.Conditions[0].RainfallDaily.Value
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	if p.Conditions[0].RainRateLast.Valid {
+		t.Fatalf("Expected null got %v", p.Conditions[0].RainRateLast)
+	}
+
+	{
+		expect := int32(1)
+		got := atomic.LoadInt32(&errs)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	// the broadcast is renewed every half second
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&requests) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected renewals got %v requests", atomic.LoadInt32(&requests))
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			// a packet may have been in flight
+			if _, ok := <-ch; ok {
+				t.Fatal("Expected the channel to be closed")
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the channel to close")
+	}
+}
+
+func TestListenerRealTimeError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	l := local.NewListener(local.NewLiveClient(ts.URL))
+	l.Address = "127.0.0.1:0"
+
+	if _, err := l.Listen(context.Background()); err == nil {
+		t.Fatal("Expected an error")
+	}
+}
@@ -0,0 +1,131 @@
+// Package local provides a client to the HTTP API that a WeatherLink Live gateway serves on the
+// local network. It needs no API key and gives new data every few seconds.
+//
+// The gateway reports in the same units as the cloud API, except that rain is counted in clicks
+// of the rain collector (see RainSize). Records can be converted into the record types of the
+// weatherlink package with Conditions.CurrentResponse, so code written for the cloud API can be
+// fed from the gateway.
+package local
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	currentConditionsPath string = "/v1/current_conditions"
+	realTimePath          string = "/v1/real_time"
+)
+
+// LiveClient makes requests to a WeatherLink Live gateway
+type LiveClient struct {
+	Client  *http.Client
+	baseURL *url.URL
+}
+
+// NewLiveClient returns a client for the gateway at address, which is a host name or IP address
+// with an optional port, or a URL such as http://192.168.1.50
+func NewLiveClient(address string) *LiveClient {
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	u, err := url.Parse(address)
+	if err != nil || u.Host == "" {
+		panic("Address must be a host or URL.")
+	}
+	return &LiveClient{
+		Client:  &http.Client{Timeout: 10 * time.Second},
+		baseURL: u,
+	}
+}
+
+// Error is returned when the gateway fails a request
+type Error struct {
+	StatusCode int
+	Code       int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("Error making local request. Got status: %d (code %d: %s)", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("Error making local request. Got status: %d", e.StatusCode)
+}
+
+// envelope is the wrapper of every gateway response
+type envelope struct {
+	Data  json.RawMessage `json:"data"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// get requests path and decodes the data of the response into v
+func (c *LiveClient) get(ctx context.Context, path string, q url.Values, v interface{}) error {
+	u := *c.baseURL
+	u.Path = strings.TrimSuffix(u.Path, "/") + path
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var env envelope
+	derr := json.NewDecoder(resp.Body).Decode(&env)
+	if resp.StatusCode != http.StatusOK || env.Error != nil {
+		e := &Error{StatusCode: resp.StatusCode}
+		if derr == nil && env.Error != nil {
+			e.Code = env.Error.Code
+			e.Message = env.Error.Message
+		}
+		return e
+	}
+	if derr != nil {
+		return derr
+	}
+	return json.Unmarshal(env.Data, v)
+}
+
+// CurrentConditions gets the latest conditions of every sensor on the gateway
+func (c *LiveClient) CurrentConditions() (cc Conditions, err error) {
+	return c.CurrentConditionsContext(context.Background())
+}
+
+// CurrentConditionsContext is like CurrentConditions but uses ctx for the request
+func (c *LiveClient) CurrentConditionsContext(ctx context.Context) (cc Conditions, err error) {
+	err = c.get(ctx, currentConditionsPath, nil, &cc)
+	return
+}
+
+// RealTime is the broadcast session started by a real time request
+type RealTime struct {
+	BroadcastPort int `json:"broadcast_port"`
+	Duration      int `json:"duration"` // seconds
+}
+
+// RealTime asks the gateway to broadcast wind and rain over UDP every 2.5 seconds for d
+func (c *LiveClient) RealTime(d time.Duration) (rt RealTime, err error) {
+	return c.RealTimeContext(context.Background(), d)
+}
+
+// RealTimeContext is like RealTime but uses ctx for the request
+func (c *LiveClient) RealTimeContext(ctx context.Context, d time.Duration) (rt RealTime, err error) {
+	q := url.Values{}
+	q.Set("duration", strconv.Itoa(int(d/time.Second)))
+	err = c.get(ctx, realTimePath, q, &rt)
+	return
+}
@@ -0,0 +1,281 @@
+package local_test
+
+import (
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexhowarth/go-weatherlink"
+	"github.com/alexhowarth/go-weatherlink/local"
+)
+
+func helperLoadBytes(t *testing.T, name string) []byte {
+	t.Helper()
+	b, err := ioutil.ReadFile(filepath.Join("..", "testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func helperServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/current_conditions":
+			w.Write(helperLoadBytes(t, "local-current-conditions.json"))
+		case "/v1/real_time":
+			if r.URL.Query().Get("duration") != "1200" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"data":null,"error":{"code":400,"message":"bad duration"}}`))
+				return
+			}
+			w.Write([]byte(`{"data":{"broadcast_port":22222,"duration":1200},"error":null}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestCurrentConditions(t *testing.T) {
+	ts := helperServer(t)
+	defer ts.Close()
+
+	lc := local.NewLiveClient(ts.URL)
+
+	cc, err := lc.CurrentConditions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	{
+		expect := "001D0A700002"
+		got := cc.Did
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := 5
+		got := len(cc.Conditions)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	iss, ok := cc.Conditions[0].(*local.ISS)
+	if !ok {
+		t.Fatalf("Expected *local.ISS got %T", cc.Conditions[0])
+	}
+
+	{
+		expect := 62.7
+		got := iss.Temp.Value
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := 25.0
+		got := iss.RainfallDaily.Value
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	sl, ok := cc.Conditions[1].(*local.SoilLeaf)
+	if !ok {
+		t.Fatalf("Expected *local.SoilLeaf got %T", cc.Conditions[1])
+	}
+
+	{
+		expect := 3187671188
+		got := sl.Lsid
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	if sl.Temp2.Valid {
+		t.Fatalf("Expected temp_2 to be missing got %v", sl.Temp2)
+	}
+
+	if _, ok := cc.Conditions[2].(*local.TempHum); !ok {
+		t.Fatalf("Expected *local.TempHum got %T", cc.Conditions[2])
+	}
+
+	if _, ok := cc.Conditions[3].(*local.Barometer); !ok {
+		t.Fatalf("Expected *local.Barometer got %T", cc.Conditions[3])
+	}
+
+	u, ok := cc.Conditions[4].(*local.Unknown)
+	if !ok {
+		t.Fatalf("Expected *local.Unknown got %T", cc.Conditions[4])
+	}
+
+	{
+		expect := "1"
+		got := string(u.Fields["something_new"])
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
+
+func TestCurrentResponse(t *testing.T) {
+	ts := helperServer(t)
+	defer ts.Close()
+
+	cc, err := local.NewLiveClient(ts.URL).CurrentConditions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cr := cc.CurrentResponse(3971)
+
+	{
+		expect := 4
+		got := len(cr.Sensors)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := 1531754005
+		got := cr.GeneratedAt
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	iss, ok := cr.Sensors[0].Data[0].(*weatherlink.ISSCurrent)
+	if !ok {
+		t.Fatalf("Expected *weatherlink.ISSCurrent got %T", cr.Sensors[0].Data[0])
+	}
+
+	{
+		expect := int64(1531754005)
+		got := iss.Timestamp()
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := 0.25
+		got := iss.RainfallDailyIn.Value
+		if math.Abs(got-expect) > 1e-9 {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := 6.35
+		got := iss.RainfallDailyMm.Value
+		if math.Abs(got-expect) > 1e-9 {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := 0.12
+		got := iss.RainRateHiIn.Value
+		if math.Abs(got-expect) > 1e-9 {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := weatherlink.SensorTypeBarometer
+		got := cr.Sensors[3].SensorType
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	bar, ok := cr.Sensors[3].Data[0].(*weatherlink.BarometerCurrent)
+	if !ok {
+		t.Fatalf("Expected *weatherlink.BarometerCurrent got %T", cr.Sensors[3].Data[0])
+	}
+
+	{
+		expect := 30.008
+		got := bar.BarSeaLevel.Value
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	// the converted response works with code written for the cloud API
+	cr.Derive(0)
+	if iss.Derived == nil || !iss.Derived.DewPoint.Valid {
+		t.Fatal("Expected derived values")
+	}
+}
+
+func TestRainSize(t *testing.T) {
+	{
+		in, mm := local.RainSize(2)
+		if mm != 0.2 || math.Abs(in-0.007874) > 1e-6 {
+			t.Fatalf("Expected 0.2mm got %v in %v mm", in, mm)
+		}
+	}
+	{
+		in, mm := local.RainSize(0)
+		if in != 0 || mm != 0 {
+			t.Fatalf("Expected 0 got %v in %v mm", in, mm)
+		}
+	}
+}
+
+func TestRealTime(t *testing.T) {
+	ts := helperServer(t)
+	defer ts.Close()
+
+	lc := local.NewLiveClient(ts.URL)
+
+	rt, err := lc.RealTime(20 * time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	{
+		expect := 22222
+		got := rt.BroadcastPort
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	_, err = lc.RealTime(time.Minute)
+	e, ok := err.(*local.Error)
+	if !ok {
+		t.Fatalf("Expected *local.Error got %v", err)
+	}
+
+	{
+		expect := "bad duration"
+		got := e.Message
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
+
+func TestNewLiveClient(t *testing.T) {
+	ts := helperServer(t)
+	defer ts.Close()
+
+	// a bare host and port is taken to be http
+	lc := local.NewLiveClient(ts.Listener.Addr().String())
+	if _, err := lc.CurrentConditions(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,329 @@
+package local
+
+import (
+	"encoding/json"
+
+	"github.com/alexhowarth/go-weatherlink"
+)
+
+// Data structure types of the local API
+const (
+	StructureISS       = 1 // ISS or other integrated sensor suite
+	StructureSoilLeaf  = 2 // soil/leaf station
+	StructureBarometer = 3 // barometer of the gateway
+	StructureTempHum   = 4 // inside temperature/humidity of the gateway
+)
+
+// Data structure types of the cloud API that local records are converted to
+const (
+	cloudISSCurrent = 10
+	cloudLSSCurrent = 12
+)
+
+// Record is one sensor's conditions. The concrete type depends on the data structure type:
+// *ISS, *SoilLeaf, *Barometer, *TempHum or *Unknown.
+type Record interface {
+	header() *Header
+}
+
+// Header holds the fields common to every record
+type Header struct {
+	Lsid              int `json:"lsid"`
+	DataStructureType int `json:"data_structure_type"`
+}
+
+func (h *Header) header() *Header {
+	return h
+}
+
+// Conditions is the response to a current conditions request
+type Conditions struct {
+	Did        string   `json:"did"` // device id of the gateway
+	Ts         int64    `json:"ts"`
+	Conditions []Record `json:"conditions"`
+}
+
+// UnmarshalJSON decodes each record into the type for its data structure type
+func (c *Conditions) UnmarshalJSON(b []byte) error {
+	var v struct {
+		Did        string            `json:"did"`
+		Ts         int64             `json:"ts"`
+		Conditions []json.RawMessage `json:"conditions"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	c.Did = v.Did
+	c.Ts = v.Ts
+	c.Conditions = make([]Record, 0, len(v.Conditions))
+	for _, raw := range v.Conditions {
+		r, err := decodeRecord(raw)
+		if err != nil {
+			return err
+		}
+		c.Conditions = append(c.Conditions, r)
+	}
+	return nil
+}
+
+func decodeRecord(raw json.RawMessage) (Record, error) {
+	var h Header
+	if err := json.Unmarshal(raw, &h); err != nil {
+		return nil, err
+	}
+	var r Record
+	switch h.DataStructureType {
+	case StructureISS:
+		r = &ISS{}
+	case StructureSoilLeaf:
+		r = &SoilLeaf{}
+	case StructureBarometer:
+		r = &Barometer{}
+	case StructureTempHum:
+		r = &TempHum{}
+	default:
+		u := &Unknown{Header: h}
+		if err := json.Unmarshal(raw, &u.Fields); err != nil {
+			return nil, err
+		}
+		return u, nil
+	}
+	if err := json.Unmarshal(raw, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Unknown holds a record of a data structure type this package does not know
+type Unknown struct {
+	Header
+	Fields map[string]json.RawMessage
+}
+
+// ISS is the conditions of an integrated sensor suite. Rain is in clicks of size RainSize.
+type ISS struct {
+	Header
+	TxID                      int               `json:"txid"`
+	Temp                      weatherlink.Float `json:"temp" unit:"degF"`
+	Hum                       weatherlink.Float `json:"hum"`
+	DewPoint                  weatherlink.Float `json:"dew_point" unit:"degF"`
+	WetBulb                   weatherlink.Float `json:"wet_bulb" unit:"degF"`
+	HeatIndex                 weatherlink.Float `json:"heat_index" unit:"degF"`
+	WindChill                 weatherlink.Float `json:"wind_chill" unit:"degF"`
+	ThwIndex                  weatherlink.Float `json:"thw_index" unit:"degF"`
+	ThswIndex                 weatherlink.Float `json:"thsw_index" unit:"degF"`
+	WindSpeedLast             weatherlink.Float `json:"wind_speed_last" unit:"mph"`
+	WindDirLast               weatherlink.Float `json:"wind_dir_last"`
+	WindSpeedAvgLast1Min      weatherlink.Float `json:"wind_speed_avg_last_1_min" unit:"mph"`
+	WindDirScalarAvgLast1Min  weatherlink.Float `json:"wind_dir_scalar_avg_last_1_min"`
+	WindSpeedAvgLast2Min      weatherlink.Float `json:"wind_speed_avg_last_2_min" unit:"mph"`
+	WindDirScalarAvgLast2Min  weatherlink.Float `json:"wind_dir_scalar_avg_last_2_min"`
+	WindSpeedHiLast2Min       weatherlink.Float `json:"wind_speed_hi_last_2_min" unit:"mph"`
+	WindDirAtHiSpeedLast2Min  weatherlink.Float `json:"wind_dir_at_hi_speed_last_2_min"`
+	WindSpeedAvgLast10Min     weatherlink.Float `json:"wind_speed_avg_last_10_min" unit:"mph"`
+	WindDirScalarAvgLast10Min weatherlink.Float `json:"wind_dir_scalar_avg_last_10_min"`
+	WindSpeedHiLast10Min      weatherlink.Float `json:"wind_speed_hi_last_10_min" unit:"mph"`
+	WindDirAtHiSpeedLast10Min weatherlink.Float `json:"wind_dir_at_hi_speed_last_10_min"`
+	RainSize                  int               `json:"rain_size"`
+	RainRateLast              weatherlink.Float `json:"rain_rate_last"` // clicks/h
+	RainRateHi                weatherlink.Float `json:"rain_rate_hi"`   // clicks/h
+	RainfallLast15Min         weatherlink.Float `json:"rainfall_last_15_min"`
+	RainRateHiLast15Min       weatherlink.Float `json:"rain_rate_hi_last_15_min"` // clicks/h
+	RainfallLast60Min         weatherlink.Float `json:"rainfall_last_60_min"`
+	RainfallLast24Hr          weatherlink.Float `json:"rainfall_last_24_hr"`
+	RainStorm                 weatherlink.Float `json:"rain_storm"`
+	RainStormStartAt          int64             `json:"rain_storm_start_at"`
+	SolarRad                  weatherlink.Float `json:"solar_rad"`
+	UvIndex                   weatherlink.Float `json:"uv_index"`
+	RxState                   int               `json:"rx_state"`
+	TransBatteryFlag          int               `json:"trans_battery_flag"`
+	RainfallDaily             weatherlink.Float `json:"rainfall_daily"`
+	RainfallMonthly           weatherlink.Float `json:"rainfall_monthly"`
+	RainfallYear              weatherlink.Float `json:"rainfall_year"`
+	RainStormLast             weatherlink.Float `json:"rain_storm_last"`
+	RainStormLastStartAt      int64             `json:"rain_storm_last_start_at"`
+	RainStormLastEndAt        int64             `json:"rain_storm_last_end_at"`
+}
+
+// SoilLeaf is the conditions of a soil/leaf station
+type SoilLeaf struct {
+	Header
+	TxID             int               `json:"txid"`
+	Temp1            weatherlink.Float `json:"temp_1" unit:"degF"`
+	Temp2            weatherlink.Float `json:"temp_2" unit:"degF"`
+	Temp3            weatherlink.Float `json:"temp_3" unit:"degF"`
+	Temp4            weatherlink.Float `json:"temp_4" unit:"degF"`
+	MoistSoil1       weatherlink.Float `json:"moist_soil_1"`
+	MoistSoil2       weatherlink.Float `json:"moist_soil_2"`
+	MoistSoil3       weatherlink.Float `json:"moist_soil_3"`
+	MoistSoil4       weatherlink.Float `json:"moist_soil_4"`
+	WetLeaf1         weatherlink.Float `json:"wet_leaf_1"`
+	WetLeaf2         weatherlink.Float `json:"wet_leaf_2"`
+	RxState          int               `json:"rx_state"`
+	TransBatteryFlag int               `json:"trans_battery_flag"`
+}
+
+// Barometer is the conditions of the gateway's barometer
+type Barometer struct {
+	Header
+	BarSeaLevel weatherlink.Float `json:"bar_sea_level" unit:"inHg"`
+	BarTrend    weatherlink.Float `json:"bar_trend" unit:"inHg"`
+	BarAbsolute weatherlink.Float `json:"bar_absolute" unit:"inHg"`
+}
+
+// TempHum is the conditions of the gateway's inside temperature/humidity sensor
+type TempHum struct {
+	Header
+	TempIn      weatherlink.Float `json:"temp_in" unit:"degF"`
+	HumIn       weatherlink.Float `json:"hum_in"`
+	DewPointIn  weatherlink.Float `json:"dew_point_in" unit:"degF"`
+	HeatIndexIn weatherlink.Float `json:"heat_index_in" unit:"degF"`
+}
+
+// RainSize returns the inches and millimetres of rain in one click of a rain collector of
+// the given size: 1 is 0.01in, 2 is 0.2mm, 3 is 0.1mm and 4 is 0.001in
+func RainSize(size int) (in float64, mm float64) {
+	switch size {
+	case 1:
+		return 0.01, 0.254
+	case 2:
+		return 0.2 / 25.4, 0.2
+	case 3:
+		return 0.1 / 25.4, 0.1
+	case 4:
+		return 0.001, 0.0254
+	}
+	return 0, 0
+}
+
+// rain converts clicks to clicks, inches and millimetres. The amounts are missing if the
+// collector size is not known.
+func rain(clicks weatherlink.Float, size int) (weatherlink.Float, weatherlink.Float, weatherlink.Float) {
+	in, mm := RainSize(size)
+	if !clicks.Valid || in == 0 {
+		return clicks, weatherlink.Float{}, weatherlink.Float{}
+	}
+	return clicks, weatherlink.NewFloat(clicks.Value * in), weatherlink.NewFloat(clicks.Value * mm)
+}
+
+// Current returns the record as the cloud API's record for an ISS on a WeatherLink Live
+func (r *ISS) Current(ts int64) *weatherlink.ISSCurrent {
+	d := &weatherlink.ISSCurrent{
+		TxID:                      r.TxID,
+		Temp:                      r.Temp,
+		Hum:                       r.Hum,
+		DewPoint:                  r.DewPoint,
+		WetBulb:                   r.WetBulb,
+		HeatIndex:                 r.HeatIndex,
+		WindChill:                 r.WindChill,
+		ThwIndex:                  r.ThwIndex,
+		ThswIndex:                 r.ThswIndex,
+		WindSpeedLast:             r.WindSpeedLast,
+		WindDirLast:               r.WindDirLast,
+		WindSpeedAvgLast1Min:      r.WindSpeedAvgLast1Min,
+		WindDirScalarAvgLast1Min:  r.WindDirScalarAvgLast1Min,
+		WindSpeedAvgLast2Min:      r.WindSpeedAvgLast2Min,
+		WindDirScalarAvgLast2Min:  r.WindDirScalarAvgLast2Min,
+		WindSpeedHiLast2Min:       r.WindSpeedHiLast2Min,
+		WindDirAtHiSpeedLast2Min:  r.WindDirAtHiSpeedLast2Min,
+		WindSpeedAvgLast10Min:     r.WindSpeedAvgLast10Min,
+		WindDirScalarAvgLast10Min: r.WindDirScalarAvgLast10Min,
+		WindSpeedHiLast10Min:      r.WindSpeedHiLast10Min,
+		WindDirAtHiSpeedLast10Min: r.WindDirAtHiSpeedLast10Min,
+		RainSize:                  r.RainSize,
+		RainStormStartAt:          r.RainStormStartAt,
+		RainStormLastStartAt:      r.RainStormLastStartAt,
+		RainStormLastEndAt:        r.RainStormLastEndAt,
+		SolarRad:                  r.SolarRad,
+		UvIndex:                   r.UvIndex,
+		RxState:                   r.RxState,
+		TransBatteryFlag:          r.TransBatteryFlag,
+	}
+	d.Ts = ts
+	d.RainRateLastClicks, d.RainRateLastIn, d.RainRateLastMm = rain(r.RainRateLast, r.RainSize)
+	d.RainRateHiClicks, d.RainRateHiIn, d.RainRateHiMm = rain(r.RainRateHi, r.RainSize)
+	d.RainfallLast15MinClicks, d.RainfallLast15MinIn, d.RainfallLast15MinMm = rain(r.RainfallLast15Min, r.RainSize)
+	d.RainRateHiLast15MinClicks, d.RainRateHiLast15MinIn, d.RainRateHiLast15MinMm = rain(r.RainRateHiLast15Min, r.RainSize)
+	d.RainfallLast60MinClicks, d.RainfallLast60MinIn, d.RainfallLast60MinMm = rain(r.RainfallLast60Min, r.RainSize)
+	d.RainfallLast24HrClicks, d.RainfallLast24HrIn, d.RainfallLast24HrMm = rain(r.RainfallLast24Hr, r.RainSize)
+	d.RainStormClicks, d.RainStormIn, d.RainStormMm = rain(r.RainStorm, r.RainSize)
+	d.RainfallDailyClicks, d.RainfallDailyIn, d.RainfallDailyMm = rain(r.RainfallDaily, r.RainSize)
+	d.RainfallMonthlyClicks, d.RainfallMonthlyIn, d.RainfallMonthlyMm = rain(r.RainfallMonthly, r.RainSize)
+	d.RainfallYearClicks, d.RainfallYearIn, d.RainfallYearMm = rain(r.RainfallYear, r.RainSize)
+	d.RainStormLastClicks, d.RainStormLastIn, d.RainStormLastMm = rain(r.RainStormLast, r.RainSize)
+	return d
+}
+
+// Current returns the record as the cloud API's record for a soil/leaf station
+func (r *SoilLeaf) Current(ts int64) *weatherlink.SoilLeafCurrent {
+	d := &weatherlink.SoilLeafCurrent{
+		TxID:             r.TxID,
+		Temp1:            r.Temp1,
+		Temp2:            r.Temp2,
+		Temp3:            r.Temp3,
+		Temp4:            r.Temp4,
+		MoistSoil1:       r.MoistSoil1,
+		MoistSoil2:       r.MoistSoil2,
+		MoistSoil3:       r.MoistSoil3,
+		MoistSoil4:       r.MoistSoil4,
+		WetLeaf1:         r.WetLeaf1,
+		WetLeaf2:         r.WetLeaf2,
+		RxState:          r.RxState,
+		TransBatteryFlag: r.TransBatteryFlag,
+	}
+	d.Ts = ts
+	return d
+}
+
+// Current returns the record as the cloud API's record for a WeatherLink Live barometer
+func (r *Barometer) Current(ts int64) *weatherlink.BarometerCurrent {
+	d := &weatherlink.BarometerCurrent{
+		BarSeaLevel: r.BarSeaLevel,
+		BarTrend:    r.BarTrend,
+		BarAbsolute: r.BarAbsolute,
+	}
+	d.Ts = ts
+	return d
+}
+
+// Current returns the record as the cloud API's record for a WeatherLink Live inside
+// temperature/humidity sensor
+func (r *TempHum) Current(ts int64) *weatherlink.TempHumCurrent {
+	d := &weatherlink.TempHumCurrent{
+		TempIn:      r.TempIn,
+		HumIn:       r.HumIn,
+		DewPointIn:  r.DewPointIn,
+		HeatIndexIn: r.HeatIndexIn,
+	}
+	d.Ts = ts
+	return d
+}
+
+// CurrentResponse returns the conditions as a response of the cloud API's current endpoint, so
+// they can be used by code written for the cloud API. Records of unknown data structure types
+// are left out. The local API does not report the sensor type of an ISS, so it is 0.
+func (c Conditions) CurrentResponse(station int) weatherlink.CurrentResponse {
+	cr := weatherlink.CurrentResponse{
+		StationID:   station,
+		GeneratedAt: int(c.Ts),
+		Sensors:     []weatherlink.CurrentSensor{},
+	}
+	for _, r := range c.Conditions {
+		var s weatherlink.CurrentSensor
+		switch r := r.(type) {
+		case *ISS:
+			s = weatherlink.CurrentSensor{DataStructureType: cloudISSCurrent, Data: []weatherlink.SensorData{r.Current(c.Ts)}}
+		case *SoilLeaf:
+			s = weatherlink.CurrentSensor{SensorType: weatherlink.SensorTypeSoilLeaf, DataStructureType: cloudLSSCurrent, Data: []weatherlink.SensorData{r.Current(c.Ts)}}
+		case *Barometer:
+			s = weatherlink.CurrentSensor{SensorType: weatherlink.SensorTypeBarometer, DataStructureType: cloudLSSCurrent, Data: []weatherlink.SensorData{r.Current(c.Ts)}}
+		case *TempHum:
+			s = weatherlink.CurrentSensor{SensorType: weatherlink.SensorTypeTempHum, DataStructureType: cloudLSSCurrent, Data: []weatherlink.SensorData{r.Current(c.Ts)}}
+		default:
+			continue
+		}
+		s.Lsid = r.header().Lsid
+		cr.Sensors = append(cr.Sensors, s)
+	}
+	return cr
+}
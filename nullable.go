@@ -0,0 +1,56 @@
+package weatherlink
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// Float is a measurement that may be missing. The API sends null for readings from sensors that
+// are absent or not reporting, which is decoded as Valid false rather than 0.
+type Float struct {
+	Value float64
+	Valid bool
+}
+
+// NewFloat returns a valid Float
+func NewFloat(v float64) Float {
+	return Float{Value: v, Valid: true}
+}
+
+// Or returns the value, or def if it is missing
+func (f Float) Or(def float64) float64 {
+	if !f.Valid {
+		return def
+	}
+	return f.Value
+}
+
+// String returns the value, or "null" if it is missing
+func (f Float) String() string {
+	if !f.Valid {
+		return "null"
+	}
+	return strconv.FormatFloat(f.Value, 'f', -1, 64)
+}
+
+// MarshalJSON writes a missing value as null
+func (f Float) MarshalJSON() ([]byte, error) {
+	if !f.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(f.Value)
+}
+
+// UnmarshalJSON reads null as a missing value
+func (f *Float) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		*f = Float{}
+		return nil
+	}
+	if err := json.Unmarshal(b, &f.Value); err != nil {
+		return err
+	}
+	f.Valid = true
+	return nil
+}
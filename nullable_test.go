@@ -0,0 +1,48 @@
+package weatherlink
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFloatJSON(t *testing.T) {
+
+	var v struct {
+		A Float `json:"a"`
+		B Float `json:"b"`
+		C Float `json:"c"`
+	}
+
+	in := `{"a":1.5,"b":null,"c":0}`
+	if err := json.Unmarshal([]byte(in), &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.A != NewFloat(1.5) {
+		t.Fatalf("Expected %v got %v", NewFloat(1.5), v.A)
+	}
+	if v.B.Valid {
+		t.Fatalf("Expected missing value got %v", v.B)
+	}
+	if v.C != NewFloat(0) {
+		t.Fatalf("Expected %v got %v", NewFloat(0), v.C)
+	}
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != in {
+		t.Fatalf("Expected %v got %v", in, string(out))
+	}
+}
+
+func TestFloatOr(t *testing.T) {
+
+	if got := (Float{}).Or(-1); got != -1 {
+		t.Fatalf("Expected %v got %v", -1, got)
+	}
+	if got := NewFloat(2).Or(-1); got != 2 {
+		t.Fatalf("Expected %v got %v", 2, got)
+	}
+}
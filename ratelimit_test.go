@@ -0,0 +1,44 @@
+package weatherlink
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+
+	l := NewLimiter(100, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the burst is free, the other two wait 10ms each
+	if got := time.Since(start); got < 15*time.Millisecond {
+		t.Fatalf("Expected to be throttled, took %v", got)
+	}
+}
+
+func TestLimiterCanceled(t *testing.T) {
+
+	l := NewLimiter(1, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if err := l.Wait(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Expected %v got %v", context.DeadlineExceeded, err)
+	}
+	if got := time.Since(start); got > 500*time.Millisecond {
+		t.Fatalf("Expected Wait to return when the context is done, took %v", got)
+	}
+}
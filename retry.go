@@ -0,0 +1,147 @@
+package weatherlink
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay  = 30 * time.Second
+)
+
+// DefaultRetryableStatus are the response statuses retried when RetryPolicy.RetryableStatus is nil
+var DefaultRetryableStatus = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy controls how failed requests are retried. Set it on Config to enable retries.
+// Each retry is signed again with a fresh timestamp. A Retry-After header from the API is
+// used in place of the computed backoff.
+type RetryPolicy struct {
+	MaxAttempts     int           // total attempts including the first, retries are disabled if less than 2
+	BaseDelay       time.Duration // delay before the first retry, doubled for each retry after (default 500ms)
+	MaxDelay        time.Duration // upper bound of the computed delay (default 30s)
+	RetryableStatus []int         // response statuses to retry (default DefaultRetryableStatus)
+}
+
+// retryable reports whether a request that produced resp and err should be tried again
+func (r *RetryPolicy) retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		// only transient transport failures; a bad scheme, TLS or DNS
+		// failure will not go away by asking again
+		var ne net.Error
+		if errors.As(err, &ne) && ne.Timeout() {
+			return true
+		}
+		return errors.Is(err, syscall.ECONNRESET) ||
+			errors.Is(err, syscall.ECONNREFUSED) ||
+			errors.Is(err, io.EOF) ||
+			errors.Is(err, io.ErrUnexpectedEOF)
+	}
+	status := r.RetryableStatus
+	if status == nil {
+		status = DefaultRetryableStatus
+	}
+	for _, s := range status {
+		if resp.StatusCode == s {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns how long to wait before retry number attempt (starting at 0)
+func (r *RetryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			return d
+		}
+	}
+
+	base := r.BaseDelay
+	if base <= 0 {
+		base = defaultRetryBaseDelay
+	}
+	max := r.MaxDelay
+	if max <= 0 {
+		max = defaultRetryMaxDelay
+	}
+
+	d := base
+	for i := 0; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+
+	// jitter over the upper half so concurrent clients spread out
+	half := int64(d / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// resign returns a copy of params with a fresh timestamp
+func (w *Client) resign(params SignatureParams) SignatureParams {
+	p := w.MakeSignatureParams()
+	for k, v := range params {
+		if k != tParam && k != keyParam {
+			p[k] = v
+		}
+	}
+	return p
+}
+
+// discard drains and closes a response body so the connection can be reused
+func discard(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
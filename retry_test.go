@@ -0,0 +1,73 @@
+package weatherlink
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+
+	{
+		got, ok := retryAfter("3")
+		expect := 3 * time.Second
+		if !ok || got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		_, ok := retryAfter("soon")
+		if ok {
+			t.Fatalf("Expected invalid Retry-After to be ignored")
+		}
+	}
+	{
+		got, ok := retryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+		if !ok || got != 0 {
+			t.Fatalf("Expected %v got %v", 0, got)
+		}
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+
+	r := &RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt, max := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		max *= time.Millisecond
+		got := r.delay(attempt, nil)
+		if got < max/2 || got > max {
+			t.Fatalf("Attempt %v expected delay within [%v, %v] got %v", attempt, max/2, max, got)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"7"}}}
+	{
+		expect := 7 * time.Second
+		got := r.delay(0, resp)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
+
+func TestResign(t *testing.T) {
+
+	conf := &Config{
+		Key:    "mykey",
+		Secret: "mysecret",
+	}
+	wl := conf.NewClient()
+
+	p := wl.MakeSignatureParams()
+	p["station-id"] = "2970"
+	p["t"] = "123"
+
+	got := wl.resign(p)
+	if got["t"] == "123" {
+		t.Fatalf("Expected a fresh timestamp")
+	}
+	if got["station-id"] != "2970" || got["api-key"] != "mykey" {
+		t.Fatalf("Expected parameters to be kept got %v", got)
+	}
+}
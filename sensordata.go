@@ -0,0 +1,262 @@
+package weatherlink
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AnySensorType registers a data structure type for every sensor type without a more specific registration
+const AnySensorType = -1
+
+// SensorData is one data record reported by a sensor. The concrete type depends on the sensor type
+// and data structure type, so use a type switch to get at the fields:
+//
+//	switch d := data.(type) {
+//	case *weatherlink.VantageCurrent:
+//		fmt.Println(d.TempOut)
+//	case *weatherlink.ISSCurrent:
+//		fmt.Println(d.Temp)
+//	}
+//
+// Types registered with RegisterSensorData implement SensorData by embedding DataHeader, which
+// keeps the fields they do not model in Extra. The types of this package write Extra back out
+// when encoded; types outside it need their own MarshalJSON to do so.
+// Measurements are tagged with the unit the API reports them in (e.g. unit:"degF"), which
+// is what the units package converts from.
+type SensorData interface {
+	Timestamp() int64
+	Time() time.Time
+	header() *DataHeader
+}
+
+// DataHeader holds the fields common to every data record
+type DataHeader struct {
+	Ts int64 `json:"ts"`
+
+	Extra  map[string]json.RawMessage `json:"-"` // fields not known to this package
+	absent map[string]bool            // fields missing from the JSON decoded
+}
+
+// Timestamp returns the Unix time of the record
+func (h *DataHeader) Timestamp() int64 {
+	return h.Ts
+}
+
+// Time returns the time of the record
+func (h *DataHeader) Time() time.Time {
+	return time.Unix(h.Ts, 0)
+}
+
+func (h *DataHeader) header() *DataHeader {
+	return h
+}
+
+// UnknownData holds a record whose sensor type and data structure type are not registered
+type UnknownData struct {
+	DataHeader
+	Fields map[string]json.RawMessage
+}
+
+// UnmarshalJSON keeps every field of the record
+func (u *UnknownData) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &u.Fields); err != nil {
+		return err
+	}
+	if ts, ok := u.Fields["ts"]; ok {
+		return json.Unmarshal(ts, &u.Ts)
+	}
+	return nil
+}
+
+// MarshalJSON writes the record as it was received
+func (u *UnknownData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.Fields)
+}
+
+type dataKey struct {
+	sensorType        int
+	dataStructureType int
+}
+
+var registry = struct {
+	sync.RWMutex
+	types map[dataKey]func() SensorData
+}{types: make(map[dataKey]func() SensorData)}
+
+// RegisterSensorData sets the type records of a sensor type and data structure type are decoded into.
+// The function must return a new pointer each time it is called. Use AnySensorType to match every
+// sensor type reporting the data structure type. Registering a pair again replaces the earlier type.
+func RegisterSensorData(sensorType int, dataStructureType int, f func() SensorData) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.types[dataKey{sensorType, dataStructureType}] = f
+}
+
+// NewSensorData returns an empty record of the type registered for a sensor type and data
+// structure type, or an *UnknownData if there is none
+func NewSensorData(sensorType int, dataStructureType int) SensorData {
+	registry.RLock()
+	defer registry.RUnlock()
+	if f, ok := registry.types[dataKey{sensorType, dataStructureType}]; ok {
+		return f()
+	}
+	if f, ok := registry.types[dataKey{AnySensorType, dataStructureType}]; ok {
+		return f()
+	}
+	return &UnknownData{}
+}
+
+// RegisteredSensorData returns an empty record of each registered type, ordered by data
+// structure type and then sensor type
+func RegisteredSensorData() []SensorData {
+	registry.RLock()
+	defer registry.RUnlock()
+	keys := make([]dataKey, 0, len(registry.types))
+	for k := range registry.types {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].dataStructureType != keys[j].dataStructureType {
+			return keys[i].dataStructureType < keys[j].dataStructureType
+		}
+		return keys[i].sensorType < keys[j].sensorType
+	})
+	out := make([]SensorData, len(keys))
+	for i, k := range keys {
+		out[i] = registry.types[k]()
+	}
+	return out
+}
+
+// decodeSensorData decodes raw records into the types registered for the sensor
+func decodeSensorData(sensorType int, dataStructureType int, raw []json.RawMessage) ([]SensorData, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	data := make([]SensorData, len(raw))
+	for i, r := range raw {
+		d := NewSensorData(sensorType, dataStructureType)
+		if err := json.Unmarshal(r, d); err != nil {
+			return nil, err
+		}
+		if _, unknown := d.(*UnknownData); !unknown {
+			h := d.header()
+			var err error
+			if h.Extra, h.absent, err = extraFields(r, d); err != nil {
+				return nil, err
+			}
+		}
+		data[i] = d
+	}
+	return data, nil
+}
+
+// CurrentSensor is the current conditions data for one sensor
+type CurrentSensor struct {
+	Lsid              int          `json:"lsid"`
+	SensorType        int          `json:"sensor_type"`
+	DataStructureType int          `json:"data_structure_type"`
+	Data              []SensorData `json:"data"`
+}
+
+// UnmarshalJSON decodes each record into the type registered for the sensor
+func (s *CurrentSensor) UnmarshalJSON(b []byte) error {
+	var aux struct {
+		Lsid              int               `json:"lsid"`
+		SensorType        int               `json:"sensor_type"`
+		DataStructureType int               `json:"data_structure_type"`
+		Data              []json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	data, err := decodeSensorData(aux.SensorType, aux.DataStructureType, aux.Data)
+	if err != nil {
+		return err
+	}
+	*s = CurrentSensor{
+		Lsid:              aux.Lsid,
+		SensorType:        aux.SensorType,
+		DataStructureType: aux.DataStructureType,
+		Data:              data,
+	}
+	return nil
+}
+
+// HistoricSensor is the historic data for one sensor
+type HistoricSensor struct {
+	Lsid              int          `json:"lsid"`
+	Data              []SensorData `json:"data"`
+	SensorType        int          `json:"sensor_type"`
+	DataStructureType int          `json:"data_structure_type"`
+}
+
+// UnmarshalJSON decodes each record into the type registered for the sensor
+func (s *HistoricSensor) UnmarshalJSON(b []byte) error {
+	var aux struct {
+		Lsid              int               `json:"lsid"`
+		Data              []json.RawMessage `json:"data"`
+		SensorType        int               `json:"sensor_type"`
+		DataStructureType int               `json:"data_structure_type"`
+	}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	data, err := decodeSensorData(aux.SensorType, aux.DataStructureType, aux.Data)
+	if err != nil {
+		return err
+	}
+	*s = HistoricSensor{
+		Lsid:              aux.Lsid,
+		Data:              data,
+		SensorType:        aux.SensorType,
+		DataStructureType: aux.DataStructureType,
+	}
+	return nil
+}
+
+// Measurement is one measurement of a record
+type Measurement struct {
+	Name  string // JSON field name
+	Unit  string // unit tag, empty if the value has no unit the units package converts
+	Value Float
+}
+
+var floatType = reflect.TypeOf(Float{})
+
+// Measurements returns the measurements of a record in field order, including those that are
+// missing. Records of unregistered types (UnknownData) have none.
+func Measurements(d SensorData) []Measurement {
+	v := reflect.ValueOf(d)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	var out []Measurement
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Type != floatType {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		out = append(out, Measurement{
+			Name:  name,
+			Unit:  f.Tag.Get("unit"),
+			Value: v.Field(i).Interface().(Float),
+		})
+	}
+	return out
+}
@@ -0,0 +1,408 @@
+package weatherlink
+
+func init() {
+	// Vantage Pro2 and Vantage Vue stations on WeatherLink IP, Vantage Connect and the console
+	RegisterSensorData(AnySensorType, 1, func() SensorData { return &VantageCurrent{} })
+	RegisterSensorData(AnySensorType, 2, func() SensorData { return &VantageCurrent{} })
+	RegisterSensorData(AnySensorType, 3, func() SensorData { return &VantageArchive{} })
+	RegisterSensorData(AnySensorType, 4, func() SensorData { return &VantageArchive{} })
+
+	// WeatherLink Live ISS
+	RegisterSensorData(AnySensorType, 10, func() SensorData { return &ISSCurrent{} })
+	RegisterSensorData(AnySensorType, 11, func() SensorData { return &ISSArchive{} })
+
+	// WeatherLink Live non-ISS sensors share data structure types 12 and 13
+	RegisterSensorData(SensorTypeSoilLeaf, 12, func() SensorData { return &SoilLeafCurrent{} })
+	RegisterSensorData(SensorTypeSoilLeaf, 13, func() SensorData { return &SoilLeafArchive{} })
+	RegisterSensorData(SensorTypeBarometer, 12, func() SensorData { return &BarometerCurrent{} })
+	RegisterSensorData(SensorTypeBarometer, 13, func() SensorData { return &BarometerArchive{} })
+	RegisterSensorData(SensorTypeTempHum, 12, func() SensorData { return &TempHumCurrent{} })
+	RegisterSensorData(SensorTypeTempHum, 13, func() SensorData { return &TempHumArchive{} })
+
+	// AirLink
+	RegisterSensorData(AnySensorType, 16, func() SensorData { return &AirLinkCurrent{} })
+	RegisterSensorData(AnySensorType, 17, func() SensorData { return &AirLinkArchive{} })
+}
+
+// Sensor types with data structures that depend on the sensor type
+const (
+	SensorTypeSoilLeaf  = 56  // Soil/leaf station on WeatherLink Live
+	SensorTypeBarometer = 242 // WeatherLink Live barometer
+	SensorTypeTempHum   = 243 // WeatherLink Live inside temperature/humidity
+)
+
+// VantageCurrent is a current conditions record from a Vantage Pro2 or Vantage Vue station
+// (data structure types 1 and 2)
+type VantageCurrent struct {
+	DataHeader
+	BarTrend          Float `json:"bar_trend" unit:"inHg"`
+	Bar               Float `json:"bar" unit:"inHg"`
+	TempIn            Float `json:"temp_in" unit:"degF"`
+	HumIn             Float `json:"hum_in"`
+	TempOut           Float `json:"temp_out" unit:"degF"`
+	WindSpeed         Float `json:"wind_speed" unit:"mph"`
+	WindSpeed10MinAvg Float `json:"wind_speed_10_min_avg" unit:"mph"`
+	WindDir           Float `json:"wind_dir"`
+	TempExtra1        Float `json:"temp_extra_1" unit:"degF"`
+	TempExtra2        Float `json:"temp_extra_2" unit:"degF"`
+	TempExtra3        Float `json:"temp_extra_3" unit:"degF"`
+	TempExtra4        Float `json:"temp_extra_4" unit:"degF"`
+	TempExtra5        Float `json:"temp_extra_5" unit:"degF"`
+	TempExtra6        Float `json:"temp_extra_6" unit:"degF"`
+	TempExtra7        Float `json:"temp_extra_7" unit:"degF"`
+	TempSoil1         Float `json:"temp_soil_1" unit:"degF"`
+	TempSoil2         Float `json:"temp_soil_2" unit:"degF"`
+	TempSoil3         Float `json:"temp_soil_3" unit:"degF"`
+	TempSoil4         Float `json:"temp_soil_4" unit:"degF"`
+	TempLeaf1         Float `json:"temp_leaf_1" unit:"degF"`
+	TempLeaf2         Float `json:"temp_leaf_2" unit:"degF"`
+	TempLeaf3         Float `json:"temp_leaf_3" unit:"degF"`
+	TempLeaf4         Float `json:"temp_leaf_4" unit:"degF"`
+	HumOut            Float `json:"hum_out"`
+	HumExtra1         Float `json:"hum_extra_1"`
+	HumExtra2         Float `json:"hum_extra_2"`
+	HumExtra3         Float `json:"hum_extra_3"`
+	HumExtra4         Float `json:"hum_extra_4"`
+	HumExtra5         Float `json:"hum_extra_5"`
+	HumExtra6         Float `json:"hum_extra_6"`
+	HumExtra7         Float `json:"hum_extra_7"`
+	RainRateClicks    Float `json:"rain_rate_clicks"`
+	RainRateIn        Float `json:"rain_rate_in" unit:"in/h"`
+	RainRateMm        Float `json:"rain_rate_mm"`
+	Uv                Float `json:"uv"`
+	SolarRad          Float `json:"solar_rad"`
+	RainStormClicks   Float `json:"rain_storm_clicks"`
+	RainStormIn       Float `json:"rain_storm_in"`
+	RainStormMm       Float `json:"rain_storm_mm"`
+	RainDayClicks     Float `json:"rain_day_clicks"`
+	RainDayIn         Float `json:"rain_day_in"`
+	RainDayMm         Float `json:"rain_day_mm"`
+	RainMonthClicks   Float `json:"rain_month_clicks"`
+	RainMonthIn       Float `json:"rain_month_in"`
+	RainMonthMm       Float `json:"rain_month_mm"`
+	RainYearClicks    Float `json:"rain_year_clicks"`
+	RainYearIn        Float `json:"rain_year_in"`
+	RainYearMm        Float `json:"rain_year_mm"`
+	EtDay             Float `json:"et_day" unit:"in"`
+	EtMonth           Float `json:"et_month" unit:"in"`
+	EtYear            Float `json:"et_year" unit:"in"`
+	MoistSoil1        Float `json:"moist_soil_1"`
+	MoistSoil2        Float `json:"moist_soil_2"`
+	MoistSoil3        Float `json:"moist_soil_3"`
+	MoistSoil4        Float `json:"moist_soil_4"`
+	WetLeaf1          Float `json:"wet_leaf_1"`
+	WetLeaf2          Float `json:"wet_leaf_2"`
+	WetLeaf3          Float `json:"wet_leaf_3"`
+	WetLeaf4          Float `json:"wet_leaf_4"`
+
+	Derived *Derived `json:"derived,omitempty"` // set by CurrentResponse.Derive
+}
+
+// VantageArchive is an archive record from a Vantage Pro2 or Vantage Vue station
+// (data structure types 3 and 4)
+type VantageArchive struct {
+	DataHeader
+	ArchInt          int   `json:"arch_int"`
+	RevType          int   `json:"rev_type"`
+	TempOut          Float `json:"temp_out" unit:"degF"`
+	TempOutHi        Float `json:"temp_out_hi" unit:"degF"`
+	TempOutLo        Float `json:"temp_out_lo" unit:"degF"`
+	TempIn           Float `json:"temp_in" unit:"degF"`
+	HumIn            Float `json:"hum_in"`
+	HumOut           Float `json:"hum_out"`
+	RainfallIn       Float `json:"rainfall_in"`
+	RainfallClicks   Float `json:"rainfall_clicks"`
+	RainfallMm       Float `json:"rainfall_mm"`
+	RainRateHiIn     Float `json:"rain_rate_hi_in" unit:"in/h"`
+	RainRateHiClicks Float `json:"rain_rate_hi_clicks"`
+	RainRateHiMm     Float `json:"rain_rate_hi_mm"`
+	Et               Float `json:"et" unit:"in"`
+	Bar              Float `json:"bar" unit:"inHg"`
+	WindNumSamples   Float `json:"wind_num_samples"`
+	WindSpeedAvg     Float `json:"wind_speed_avg" unit:"mph"`
+	WindSpeedHi      Float `json:"wind_speed_hi" unit:"mph"`
+	WindDirOfHi      Float `json:"wind_dir_of_hi"`
+	WindDirOfPrevail Float `json:"wind_dir_of_prevail"`
+	ForecastRule     Float `json:"forecast_rule"`
+	AbsPress         Float `json:"abs_press" unit:"inHg"`
+	BarNoaa          Float `json:"bar_noaa" unit:"inHg"`
+	DewPointOut      Float `json:"dew_point_out" unit:"degF"`
+	DewPointIn       Float `json:"dew_point_in" unit:"degF"`
+	Emc              Float `json:"emc"`
+	HeatIndexOut     Float `json:"heat_index_out" unit:"degF"`
+	HeatIndexIn      Float `json:"heat_index_in" unit:"degF"`
+	WindChill        Float `json:"wind_chill" unit:"degF"`
+	WindRun          Float `json:"wind_run" unit:"mi"`
+	DegDaysHeat      Float `json:"deg_days_heat"`
+	DegDaysCool      Float `json:"deg_days_cool"`
+	ThwIndex         Float `json:"thw_index" unit:"degF"`
+	WetBulb          Float `json:"wet_bulb" unit:"degF"`
+}
+
+// ISSCurrent is a current conditions record from an ISS on WeatherLink Live (data structure type 10)
+type ISSCurrent struct {
+	DataHeader
+	TxID                      int   `json:"tx_id"`
+	Temp                      Float `json:"temp" unit:"degF"`
+	Hum                       Float `json:"hum"`
+	DewPoint                  Float `json:"dew_point" unit:"degF"`
+	WetBulb                   Float `json:"wet_bulb" unit:"degF"`
+	HeatIndex                 Float `json:"heat_index" unit:"degF"`
+	WindChill                 Float `json:"wind_chill" unit:"degF"`
+	ThwIndex                  Float `json:"thw_index" unit:"degF"`
+	ThswIndex                 Float `json:"thsw_index" unit:"degF"`
+	WindSpeedLast             Float `json:"wind_speed_last" unit:"mph"`
+	WindDirLast               Float `json:"wind_dir_last"`
+	WindSpeedAvgLast1Min      Float `json:"wind_speed_avg_last_1_min" unit:"mph"`
+	WindDirScalarAvgLast1Min  Float `json:"wind_dir_scalar_avg_last_1_min"`
+	WindSpeedAvgLast2Min      Float `json:"wind_speed_avg_last_2_min" unit:"mph"`
+	WindDirScalarAvgLast2Min  Float `json:"wind_dir_scalar_avg_last_2_min"`
+	WindSpeedHiLast2Min       Float `json:"wind_speed_hi_last_2_min" unit:"mph"`
+	WindDirAtHiSpeedLast2Min  Float `json:"wind_dir_at_hi_speed_last_2_min"`
+	WindSpeedAvgLast10Min     Float `json:"wind_speed_avg_last_10_min" unit:"mph"`
+	WindDirScalarAvgLast10Min Float `json:"wind_dir_scalar_avg_last_10_min"`
+	WindSpeedHiLast10Min      Float `json:"wind_speed_hi_last_10_min" unit:"mph"`
+	WindDirAtHiSpeedLast10Min Float `json:"wind_dir_at_hi_speed_last_10_min"`
+	RainSize                  int   `json:"rain_size"`
+	RainRateLastClicks        Float `json:"rain_rate_last_clicks"`
+	RainRateLastIn            Float `json:"rain_rate_last_in" unit:"in/h"`
+	RainRateLastMm            Float `json:"rain_rate_last_mm"`
+	RainRateHiClicks          Float `json:"rain_rate_hi_clicks"`
+	RainRateHiIn              Float `json:"rain_rate_hi_in" unit:"in/h"`
+	RainRateHiMm              Float `json:"rain_rate_hi_mm"`
+	RainfallLast15MinClicks   Float `json:"rainfall_last_15_min_clicks"`
+	RainfallLast15MinIn       Float `json:"rainfall_last_15_min_in"`
+	RainfallLast15MinMm       Float `json:"rainfall_last_15_min_mm"`
+	RainRateHiLast15MinClicks Float `json:"rain_rate_hi_last_15_min_clicks"`
+	RainRateHiLast15MinIn     Float `json:"rain_rate_hi_last_15_min_in" unit:"in/h"`
+	RainRateHiLast15MinMm     Float `json:"rain_rate_hi_last_15_min_mm"`
+	RainfallLast60MinClicks   Float `json:"rainfall_last_60_min_clicks"`
+	RainfallLast60MinIn       Float `json:"rainfall_last_60_min_in"`
+	RainfallLast60MinMm       Float `json:"rainfall_last_60_min_mm"`
+	RainfallLast24HrClicks    Float `json:"rainfall_last_24_hr_clicks"`
+	RainfallLast24HrIn        Float `json:"rainfall_last_24_hr_in"`
+	RainfallLast24HrMm        Float `json:"rainfall_last_24_hr_mm"`
+	RainStormClicks           Float `json:"rain_storm_clicks"`
+	RainStormIn               Float `json:"rain_storm_in"`
+	RainStormMm               Float `json:"rain_storm_mm"`
+	RainStormStartAt          int64 `json:"rain_storm_start_at"`
+	RainfallDailyClicks       Float `json:"rainfall_daily_clicks"`
+	RainfallDailyIn           Float `json:"rainfall_daily_in"`
+	RainfallDailyMm           Float `json:"rainfall_daily_mm"`
+	RainfallMonthlyClicks     Float `json:"rainfall_monthly_clicks"`
+	RainfallMonthlyIn         Float `json:"rainfall_monthly_in"`
+	RainfallMonthlyMm         Float `json:"rainfall_monthly_mm"`
+	RainfallYearClicks        Float `json:"rainfall_year_clicks"`
+	RainfallYearIn            Float `json:"rainfall_year_in"`
+	RainfallYearMm            Float `json:"rainfall_year_mm"`
+	RainStormLastClicks       Float `json:"rain_storm_last_clicks"`
+	RainStormLastIn           Float `json:"rain_storm_last_in"`
+	RainStormLastMm           Float `json:"rain_storm_last_mm"`
+	RainStormLastStartAt      int64 `json:"rain_storm_last_start_at"`
+	RainStormLastEndAt        int64 `json:"rain_storm_last_end_at"`
+	SolarRad                  Float `json:"solar_rad"`
+	UvIndex                   Float `json:"uv_index"`
+	RxState                   int   `json:"rx_state"`
+	TransBatteryFlag          int   `json:"trans_battery_flag"`
+
+	Derived *Derived `json:"derived,omitempty"` // set by CurrentResponse.Derive
+}
+
+// ISSArchive is an archive record from an ISS on WeatherLink Live (data structure type 11)
+type ISSArchive struct {
+	DataHeader
+	TxID             int   `json:"tx_id"`
+	TempLast         Float `json:"temp_last" unit:"degF"`
+	TempAvg          Float `json:"temp_avg" unit:"degF"`
+	TempHi           Float `json:"temp_hi" unit:"degF"`
+	TempHiAt         int64 `json:"temp_hi_at"`
+	TempLo           Float `json:"temp_lo" unit:"degF"`
+	TempLoAt         int64 `json:"temp_lo_at"`
+	HumLast          Float `json:"hum_last"`
+	HumHi            Float `json:"hum_hi"`
+	HumLo            Float `json:"hum_lo"`
+	DewPointLast     Float `json:"dew_point_last" unit:"degF"`
+	DewPointHi       Float `json:"dew_point_hi" unit:"degF"`
+	DewPointLo       Float `json:"dew_point_lo" unit:"degF"`
+	WetBulbLast      Float `json:"wet_bulb_last" unit:"degF"`
+	HeatIndexLast    Float `json:"heat_index_last" unit:"degF"`
+	HeatIndexHi      Float `json:"heat_index_hi" unit:"degF"`
+	WindChillLast    Float `json:"wind_chill_last" unit:"degF"`
+	WindChillLo      Float `json:"wind_chill_lo" unit:"degF"`
+	ThwIndexLast     Float `json:"thw_index_last" unit:"degF"`
+	ThwIndexHi       Float `json:"thw_index_hi" unit:"degF"`
+	ThwIndexLo       Float `json:"thw_index_lo" unit:"degF"`
+	ThswIndexLast    Float `json:"thsw_index_last" unit:"degF"`
+	ThswIndexHi      Float `json:"thsw_index_hi" unit:"degF"`
+	ThswIndexLo      Float `json:"thsw_index_lo" unit:"degF"`
+	WindSpeedAvg     Float `json:"wind_speed_avg" unit:"mph"`
+	WindSpeedHi      Float `json:"wind_speed_hi" unit:"mph"`
+	WindSpeedHiAt    int64 `json:"wind_speed_hi_at"`
+	WindSpeedHiDir   Float `json:"wind_speed_hi_dir"`
+	WindDirOfPrevail Float `json:"wind_dir_of_prevail"`
+	WindRun          Float `json:"wind_run" unit:"mi"`
+	RainSize         int   `json:"rain_size"`
+	RainfallClicks   Float `json:"rainfall_clicks"`
+	RainfallIn       Float `json:"rainfall_in"`
+	RainfallMm       Float `json:"rainfall_mm"`
+	RainRateHiClicks Float `json:"rain_rate_hi_clicks"`
+	RainRateHiIn     Float `json:"rain_rate_hi_in" unit:"in/h"`
+	RainRateHiMm     Float `json:"rain_rate_hi_mm"`
+	RainRateHiAt     int64 `json:"rain_rate_hi_at"`
+	SolarRadAvg      Float `json:"solar_rad_avg"`
+	SolarRadHi       Float `json:"solar_rad_hi"`
+	SolarEnergy      Float `json:"solar_energy"`
+	Et               Float `json:"et" unit:"in"`
+	UvIndexAvg       Float `json:"uv_index_avg"`
+	UvIndexHi        Float `json:"uv_index_hi"`
+	UvDose           Float `json:"uv_dose"`
+	DegDaysHeat      Float `json:"deg_days_heat"`
+	DegDaysCool      Float `json:"deg_days_cool"`
+	RxState          int   `json:"rx_state"`
+	TransBatteryFlag int   `json:"trans_battery_flag"`
+	Reception        Float `json:"reception"`
+	RssiLast         Float `json:"rssi_last"`
+}
+
+// SoilLeafCurrent is a current conditions record from a soil/leaf station on WeatherLink Live
+// (data structure type 12)
+type SoilLeafCurrent struct {
+	DataHeader
+	TxID             int   `json:"tx_id"`
+	Temp1            Float `json:"temp_1" unit:"degF"`
+	Temp2            Float `json:"temp_2" unit:"degF"`
+	Temp3            Float `json:"temp_3" unit:"degF"`
+	Temp4            Float `json:"temp_4" unit:"degF"`
+	MoistSoil1       Float `json:"moist_soil_1"`
+	MoistSoil2       Float `json:"moist_soil_2"`
+	MoistSoil3       Float `json:"moist_soil_3"`
+	MoistSoil4       Float `json:"moist_soil_4"`
+	WetLeaf1         Float `json:"wet_leaf_1"`
+	WetLeaf2         Float `json:"wet_leaf_2"`
+	RxState          int   `json:"rx_state"`
+	TransBatteryFlag int   `json:"trans_battery_flag"`
+}
+
+// SoilLeafArchive is an archive record from a soil/leaf station on WeatherLink Live
+// (data structure type 13)
+type SoilLeafArchive struct {
+	DataHeader
+	TxID             int   `json:"tx_id"`
+	TempLast1        Float `json:"temp_last_1" unit:"degF"`
+	TempLast2        Float `json:"temp_last_2" unit:"degF"`
+	TempLast3        Float `json:"temp_last_3" unit:"degF"`
+	TempLast4        Float `json:"temp_last_4" unit:"degF"`
+	MoistSoilLast1   Float `json:"moist_soil_last_1"`
+	MoistSoilLast2   Float `json:"moist_soil_last_2"`
+	MoistSoilLast3   Float `json:"moist_soil_last_3"`
+	MoistSoilLast4   Float `json:"moist_soil_last_4"`
+	WetLeafLast1     Float `json:"wet_leaf_last_1"`
+	WetLeafLast2     Float `json:"wet_leaf_last_2"`
+	RxState          int   `json:"rx_state"`
+	TransBatteryFlag int   `json:"trans_battery_flag"`
+}
+
+// BarometerCurrent is a current conditions record from the WeatherLink Live barometer
+// (data structure type 12)
+type BarometerCurrent struct {
+	DataHeader
+	BarSeaLevel Float `json:"bar_sea_level" unit:"inHg"`
+	BarTrend    Float `json:"bar_trend" unit:"inHg"`
+	BarAbsolute Float `json:"bar_absolute" unit:"inHg"`
+	BarOffset   Float `json:"bar_offset" unit:"inHg"`
+
+	Derived *Derived `json:"derived,omitempty"` // set by CurrentResponse.Derive
+}
+
+// BarometerArchive is an archive record from the WeatherLink Live barometer (data structure type 13)
+type BarometerArchive struct {
+	DataHeader
+	BarSeaLevel Float `json:"bar_sea_level" unit:"inHg"`
+	BarHi       Float `json:"bar_hi" unit:"inHg"`
+	BarHiAt     int64 `json:"bar_hi_at"`
+	BarLo       Float `json:"bar_lo" unit:"inHg"`
+	BarLoAt     int64 `json:"bar_lo_at"`
+	BarAbsolute Float `json:"bar_absolute" unit:"inHg"`
+}
+
+// TempHumCurrent is a current conditions record from the WeatherLink Live inside temperature/humidity
+// sensor (data structure type 12)
+type TempHumCurrent struct {
+	DataHeader
+	TempIn      Float `json:"temp_in" unit:"degF"`
+	HumIn       Float `json:"hum_in"`
+	DewPointIn  Float `json:"dew_point_in" unit:"degF"`
+	HeatIndexIn Float `json:"heat_index_in" unit:"degF"`
+}
+
+// TempHumArchive is an archive record from the WeatherLink Live inside temperature/humidity sensor
+// (data structure type 13)
+type TempHumArchive struct {
+	DataHeader
+	TempInLast      Float `json:"temp_in_last" unit:"degF"`
+	TempInHi        Float `json:"temp_in_hi" unit:"degF"`
+	TempInLo        Float `json:"temp_in_lo" unit:"degF"`
+	HumInLast       Float `json:"hum_in_last"`
+	HumInHi         Float `json:"hum_in_hi"`
+	HumInLo         Float `json:"hum_in_lo"`
+	DewPointInLast  Float `json:"dew_point_in_last" unit:"degF"`
+	HeatIndexInLast Float `json:"heat_index_in_last" unit:"degF"`
+}
+
+// AirLinkCurrent is a current conditions record from an AirLink air quality sensor
+// (data structure type 16)
+type AirLinkCurrent struct {
+	DataHeader
+	Temp                Float  `json:"temp" unit:"degF"`
+	Hum                 Float  `json:"hum"`
+	DewPoint            Float  `json:"dew_point" unit:"degF"`
+	WetBulb             Float  `json:"wet_bulb" unit:"degF"`
+	HeatIndex           Float  `json:"heat_index" unit:"degF"`
+	Pm1                 Float  `json:"pm_1"`
+	Pm2p5               Float  `json:"pm_2p5"`
+	Pm10                Float  `json:"pm_10"`
+	Pm2p5Last1Hour      Float  `json:"pm_2p5_last_1_hour"`
+	Pm2p5Last3Hours     Float  `json:"pm_2p5_last_3_hours"`
+	Pm2p5Last24Hours    Float  `json:"pm_2p5_last_24_hours"`
+	Pm2p5Nowcast        Float  `json:"pm_2p5_nowcast"`
+	Pm10Last1Hour       Float  `json:"pm_10_last_1_hour"`
+	Pm10Last3Hours      Float  `json:"pm_10_last_3_hours"`
+	Pm10Last24Hours     Float  `json:"pm_10_last_24_hours"`
+	Pm10Nowcast         Float  `json:"pm_10_nowcast"`
+	PctPmDataLast1Hour  Float  `json:"pct_pm_data_last_1_hour"`
+	PctPmDataLast3Hours Float  `json:"pct_pm_data_last_3_hours"`
+	PctPmDataNowcast    Float  `json:"pct_pm_data_nowcast"`
+	AqiType             string `json:"aqi_type"`
+	AqiVal              Float  `json:"aqi_val"`
+	AqiDesc             string `json:"aqi_desc"`
+	Aqi1HourVal         Float  `json:"aqi_1_hour_val"`
+	Aqi1HourDesc        string `json:"aqi_1_hour_desc"`
+	AqiNowcastVal       Float  `json:"aqi_nowcast_val"`
+	AqiNowcastDesc      string `json:"aqi_nowcast_desc"`
+	LastReportTime      int64  `json:"last_report_time"`
+}
+
+// AirLinkArchive is an archive record from an AirLink air quality sensor (data structure type 17)
+type AirLinkArchive struct {
+	DataHeader
+	ArchInt      int    `json:"arch_int"`
+	TempAvg      Float  `json:"temp_avg" unit:"degF"`
+	TempHi       Float  `json:"temp_hi" unit:"degF"`
+	TempLo       Float  `json:"temp_lo" unit:"degF"`
+	HumLast      Float  `json:"hum_last"`
+	HumHi        Float  `json:"hum_hi"`
+	HumLo        Float  `json:"hum_lo"`
+	DewPointLast Float  `json:"dew_point_last" unit:"degF"`
+	WetBulbLast  Float  `json:"wet_bulb_last" unit:"degF"`
+	Pm1Avg       Float  `json:"pm_1_avg"`
+	Pm1Hi        Float  `json:"pm_1_hi"`
+	Pm2p5Avg     Float  `json:"pm_2p5_avg"`
+	Pm2p5Hi      Float  `json:"pm_2p5_hi"`
+	Pm10Avg      Float  `json:"pm_10_avg"`
+	Pm10Hi       Float  `json:"pm_10_hi"`
+	AqiType      string `json:"aqi_type"`
+	AqiAvgVal    Float  `json:"aqi_avg_val"`
+	AqiAvgDesc   string `json:"aqi_avg_desc"`
+	AqiHiVal     Float  `json:"aqi_hi_val"`
+	AqiHiDesc    string `json:"aqi_hi_desc"`
+}
@@ -0,0 +1,241 @@
+// Package table writes historic records as CSV or TSV, one row per record with a header row.
+//
+// Columns are named by the JSON fields of the records (ts, temp_out, bar, ...) plus station_id,
+// lsid, sensor_type and data_structure_type. A column a record does not have is left empty, but
+// a column no record type has is an error.
+package table
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexhowarth/go-weatherlink"
+)
+
+// TimeFormat is how the ts column is written
+type TimeFormat int
+
+// Time formats of the ts column
+const (
+	Unix    TimeFormat = iota // Unix seconds as sent by the API
+	RFC3339                   // RFC3339 in UTC
+	Local                     // RFC3339 in Options.Location, e.g. the station's time zone
+)
+
+// ParseTimeFormat returns the TimeFormat called unix, rfc3339 or local
+func ParseTimeFormat(s string) (TimeFormat, error) {
+	switch strings.ToLower(s) {
+	case "unix":
+		return Unix, nil
+	case "rfc3339":
+		return RFC3339, nil
+	case "local":
+		return Local, nil
+	}
+	return 0, fmt.Errorf("table: unknown time format %q", s)
+}
+
+// Options control the output of a Writer
+type Options struct {
+	Columns    []string       // columns to write; ts, station_id, lsid, sensor_type and every measurement if empty (see DefaultColumns)
+	Comma      rune           // field separator (default ',', use '\t' for TSV)
+	TimeFormat TimeFormat     // format of the ts column (default Unix)
+	Location   *time.Location // time zone of the Local time format (default UTC)
+	Null       string         // written for missing values (default an empty cell)
+}
+
+// Writer writes records as rows
+type Writer struct {
+	w        *csv.Writer
+	opts     Options
+	columns  []string
+	header   bool
+	defaults map[string]bool // the columns, if they were chosen from the first record
+}
+
+// NewWriter returns a Writer writing to w
+func NewWriter(w io.Writer, opts Options) *Writer {
+	cw := csv.NewWriter(w)
+	if opts.Comma != 0 {
+		cw.Comma = opts.Comma
+	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	return &Writer{
+		w:       cw,
+		opts:    opts,
+		columns: opts.Columns,
+	}
+}
+
+// Columns returns the columns written, which are known once the first record is written if
+// Options.Columns was empty
+func (w *Writer) Columns() []string {
+	return w.columns
+}
+
+// WriteHistoric writes the records of a historic response in time order. If Options.Columns
+// is empty the columns are the DefaultColumns of every record.
+func (w *Writer) WriteHistoric(hr weatherlink.HistoricResponse) error {
+	records := hr.Records()
+	if len(w.columns) == 0 {
+		w.columns = DefaultColumns(records)
+	}
+	for _, r := range records {
+		if err := w.Write(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write writes one record, preceded by the header row if it is the first. If Options.Columns
+// is empty the columns are the DefaultColumns of the first record, and a later record with
+// measurements not among them is an error: set the columns, or use DefaultColumns on enough
+// records to see every sensor.
+func (w *Writer) Write(r weatherlink.HistoricRecord) error {
+	if !w.header {
+		if len(w.columns) == 0 {
+			w.columns = DefaultColumns([]weatherlink.HistoricRecord{r})
+			w.defaults = make(map[string]bool)
+			for _, c := range w.columns {
+				w.defaults[c] = true
+			}
+		}
+		if err := checkColumns(w.columns, r.Data); err != nil {
+			return err
+		}
+		if err := w.w.Write(w.columns); err != nil {
+			return err
+		}
+		w.header = true
+	}
+
+	if w.defaults != nil {
+		for _, m := range weatherlink.Measurements(r.Data) {
+			if !w.defaults[m.Name] {
+				return fmt.Errorf("table: lsid %d has column %q, which is not in the header", r.Lsid, m.Name)
+			}
+		}
+	}
+
+	fields, err := values(r.Data)
+	if err != nil {
+		return err
+	}
+	row := make([]string, len(w.columns))
+	for i, c := range w.columns {
+		switch c {
+		case "ts":
+			row[i] = w.time(r)
+		case "station_id":
+			row[i] = strconv.Itoa(r.StationID)
+		case "lsid":
+			row[i] = strconv.Itoa(r.Lsid)
+		case "sensor_type":
+			row[i] = strconv.Itoa(r.SensorType)
+		case "data_structure_type":
+			row[i] = strconv.Itoa(r.DataStructureType)
+		default:
+			row[i] = w.value(fields[c])
+		}
+	}
+	return w.w.Write(row)
+}
+
+// Flush writes any buffered rows and returns the first error of any write
+func (w *Writer) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// time formats the ts column of r
+func (w *Writer) time(r weatherlink.HistoricRecord) string {
+	t := r.Time
+	if t.IsZero() && r.Data != nil {
+		t = r.Data.Time()
+	}
+	switch w.opts.TimeFormat {
+	case RFC3339:
+		return t.UTC().Format(time.RFC3339)
+	case Local:
+		return t.In(w.opts.Location).Format(time.RFC3339)
+	}
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
+// value formats a JSON value as a cell
+func (w *Writer) value(raw json.RawMessage) string {
+	s := string(raw)
+	if s == "" || s == "null" {
+		return w.opts.Null
+	}
+	if s[0] == '"' {
+		var str string
+		if json.Unmarshal(raw, &str) == nil {
+			return str
+		}
+	}
+	return s
+}
+
+// values returns the JSON fields of a record
+func values(d weatherlink.SensorData) (map[string]json.RawMessage, error) {
+	if d == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DefaultColumns returns ts, station_id, lsid and sensor_type followed by the measurements of
+// every record, in the order they are first seen
+func DefaultColumns(records []weatherlink.HistoricRecord) []string {
+	columns := []string{"ts", "station_id", "lsid", "sensor_type"}
+	seen := make(map[string]bool)
+	for _, r := range records {
+		for _, m := range weatherlink.Measurements(r.Data) {
+			if !seen[m.Name] {
+				seen[m.Name] = true
+				columns = append(columns, m.Name)
+			}
+		}
+	}
+	return columns
+}
+
+// checkColumns returns an error for the first column that neither a registered record type nor
+// d (which may be of an unregistered type) has
+func checkColumns(columns []string, d weatherlink.SensorData) error {
+	known := map[string]bool{
+		"ts": true, "station_id": true, "lsid": true, "sensor_type": true, "data_structure_type": true,
+	}
+	for _, rd := range append(weatherlink.RegisteredSensorData(), d) {
+		fields, err := values(rd)
+		if err != nil {
+			return err
+		}
+		for k := range fields {
+			known[k] = true
+		}
+	}
+	for _, c := range columns {
+		if !known[c] {
+			return fmt.Errorf("table: unknown column %q", c)
+		}
+	}
+	return nil
+}
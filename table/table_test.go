@@ -0,0 +1,234 @@
+package table_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexhowarth/go-weatherlink"
+	"github.com/alexhowarth/go-weatherlink/table"
+)
+
+func helperLoadHistoric(t *testing.T) weatherlink.HistoricResponse {
+	t.Helper()
+	b, err := ioutil.ReadFile(filepath.Join("..", "testdata", "historic.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hr weatherlink.HistoricResponse
+	if err := json.Unmarshal(b, &hr); err != nil {
+		t.Fatal(err)
+	}
+	return hr
+}
+
+func helperLines(t *testing.T, buf *bytes.Buffer) []string {
+	t.Helper()
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+}
+
+func TestWriteColumns(t *testing.T) {
+	var buf bytes.Buffer
+	w := table.NewWriter(&buf, table.Options{Columns: []string{"ts", "temp_out", "bar", "arch_int"}})
+	if err := w.WriteHistoric(helperLoadHistoric(t)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := helperLines(t, &buf)
+
+	{
+		expect := 13
+		got := len(lines)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := "ts,temp_out,bar,arch_int"
+		got := lines[0]
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := "1591981500,80.6,30.105,300"
+		got := lines[1]
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
+
+func TestWriteTSV(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	var buf bytes.Buffer
+	w := table.NewWriter(&buf, table.Options{
+		Columns:    []string{"ts", "lsid", "temp_out", "temp_extra_1"},
+		Comma:      '\t',
+		TimeFormat: table.Local,
+		Location:   loc,
+		Null:       "NA",
+	})
+	if err := w.WriteHistoric(helperLoadHistoric(t)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := helperLines(t, &buf)
+
+	{
+		expect := "2020-06-12T13:05:00-04:00\t12822\t80.6\tNA"
+		got := lines[1]
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
+
+func TestWriteDefaultColumns(t *testing.T) {
+	var buf bytes.Buffer
+	w := table.NewWriter(&buf, table.Options{TimeFormat: table.RFC3339})
+	if err := w.WriteHistoric(helperLoadHistoric(t)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := helperLines(t, &buf)
+
+	{
+		expect := "ts,station_id,lsid,sensor_type,temp_out,"
+		got := lines[0]
+		if !strings.HasPrefix(got, expect) {
+			t.Fatalf("Expected prefix %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := "2020-06-12T17:05:00Z,2970,12822,37,80.6,"
+		got := lines[1]
+		if !strings.HasPrefix(got, expect) {
+			t.Fatalf("Expected prefix %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := len(w.Columns())
+		got := len(strings.Split(lines[1], ","))
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
+
+func TestWriteSensors(t *testing.T) {
+	hr := helperLoadHistoric(t)
+
+	// add a barometer, which reports different measurements to the Vantage console
+	bar := &weatherlink.BarometerArchive{BarSeaLevel: weatherlink.NewFloat(30.1)}
+	bar.Ts = hr.Sensors[0].Data[0].Timestamp()
+	hr.Sensors = append(hr.Sensors, weatherlink.HistoricSensor{
+		Lsid:              99,
+		SensorType:        weatherlink.SensorTypeBarometer,
+		DataStructureType: 13,
+		Data:              []weatherlink.SensorData{bar},
+	})
+
+	var buf bytes.Buffer
+	w := table.NewWriter(&buf, table.Options{})
+	if err := w.WriteHistoric(hr); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := helperLines(t, &buf)
+	col := -1
+	for i, c := range w.Columns() {
+		if c == "bar_sea_level" {
+			col = i
+		}
+	}
+	if col < 0 {
+		t.Fatalf("Expected a bar_sea_level column got %v", w.Columns())
+	}
+
+	rows := map[string]string{}
+	for _, l := range lines[1:] {
+		cells := strings.Split(l, ",")
+		if cells[0] == "1591981500" {
+			rows[cells[2]] = cells[col]
+		}
+	}
+	{
+		expect := "30.1"
+		got := rows["99"]
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := ""
+		got := rows["12822"]
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	// streaming, the header is chosen before the barometer is seen
+	sw := table.NewWriter(ioutil.Discard, table.Options{})
+	var err error
+	for _, r := range hr.Records() {
+		if err = sw.Write(r); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		t.Fatalf("Expected an error for measurements missing from the header")
+	}
+}
+
+func TestWriteUnknownColumn(t *testing.T) {
+	hr := helperLoadHistoric(t)
+
+	// a column of another registered type is fine
+	w := table.NewWriter(ioutil.Discard, table.Options{Columns: []string{"ts", "bar_sea_level"}})
+	if err := w.WriteHistoric(hr); err != nil {
+		t.Fatal(err)
+	}
+
+	w = table.NewWriter(ioutil.Discard, table.Options{Columns: []string{"ts", "temp_ot"}})
+	if err := w.WriteHistoric(hr); err == nil {
+		t.Fatalf("Expected an error for an unknown column")
+	}
+}
+
+func TestParseTimeFormat(t *testing.T) {
+	{
+		expect := table.Local
+		got, err := table.ParseTimeFormat("local")
+		if err != nil || got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	if _, err := table.ParseTimeFormat("iso"); err == nil {
+		t.Fatal("Expected an error")
+	}
+}
@@ -0,0 +1,76 @@
+package weatherlink
+
+import (
+	"fmt"
+	"time"
+)
+
+// Location returns the station's time zone, or UTC if it is not set or not known to the
+// system time zone database. Use LoadLocation to tell those cases apart.
+func (s Station) Location() *time.Location {
+	loc, err := s.LoadLocation()
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// LoadLocation returns the station's time zone. It fails if the zone is not set or not known
+// to the system time zone database.
+func (s Station) LoadLocation() (*time.Location, error) {
+	if s.TimeZone == "" {
+		return nil, fmt.Errorf("station %d has no time zone", s.StationID)
+	}
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("station %d: %v", s.StationID, err)
+	}
+	return loc, nil
+}
+
+// Registered returns the time the station was registered, in the station's time zone
+func (s Station) Registered() time.Time {
+	return time.Unix(int64(s.RegisteredDate), 0).In(s.Location())
+}
+
+// SubscriptionEnd returns the time the station's subscription ends, in the station's time zone
+func (s Station) SubscriptionEnd() time.Time {
+	return time.Unix(int64(s.SubscriptionEndDate), 0).In(s.Location())
+}
+
+// Day returns the start and end of the day containing t in the station's time zone
+func (s Station) Day(t time.Time) (start time.Time, end time.Time) {
+	t = t.In(s.Location())
+	start = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return start, start.AddDate(0, 0, 1)
+}
+
+// Generated returns the time the response was generated
+func (s StationsResponse) Generated() time.Time {
+	return time.Unix(int64(s.GeneratedAt), 0)
+}
+
+// Generated returns the time the response was generated
+func (s SensorsResponse) Generated() time.Time {
+	return time.Unix(int64(s.GeneratedAt), 0)
+}
+
+// Generated returns the time the response was generated
+func (s SensorActivityResponse) Generated() time.Time {
+	return time.Unix(int64(s.GeneratedAt), 0)
+}
+
+// Generated returns the time the response was generated
+func (n NodesResponse) Generated() time.Time {
+	return time.Unix(int64(n.GeneratedAt), 0)
+}
+
+// Generated returns the time the response was generated
+func (c CurrentResponse) Generated() time.Time {
+	return time.Unix(int64(c.GeneratedAt), 0)
+}
+
+// Generated returns the time the response was generated
+func (h HistoricResponse) Generated() time.Time {
+	return time.Unix(int64(h.GeneratedAt), 0)
+}
@@ -0,0 +1,124 @@
+package units
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/alexhowarth/go-weatherlink"
+)
+
+// System is the unit each kind of value is converted to. An empty Unit leaves values of that kind as they are.
+type System struct {
+	Temperature Unit
+	Pressure    Unit
+	Speed       Unit
+	Rain        Unit // rainfall and evapotranspiration, reported in inches
+	RainRate    Unit // rain rate, reported in inches per hour
+	Distance    Unit // wind run, reported in miles
+	Height      Unit // elevation, reported in feet
+}
+
+// Unit systems for Convert. SI keeps rain in millimetres, as metres are impractical for rainfall.
+var (
+	Imperial = System{DegF, InHg, Mph, Inch, InchPerHour, Mile, Foot}
+	Metric   = System{DegC, HPa, Kmh, Mm, MmPerHour, Km, M}
+	SI       = System{K, Pa, Ms, Mm, MmPerHour, M, M}
+)
+
+// ParseSystem returns the System called metric, imperial or si
+func ParseSystem(name string) (System, error) {
+	switch strings.ToLower(name) {
+	case "imperial":
+		return Imperial, nil
+	case "metric":
+		return Metric, nil
+	case "si":
+		return SI, nil
+	}
+	return System{}, fmt.Errorf("unknown unit system %q, expected metric, imperial or si", name)
+}
+
+// target returns the unit a value reported in from is converted to
+func (s System) target(from Unit) Unit {
+	switch table[from].dim {
+	case temperature:
+		return s.Temperature
+	case pressure:
+		return s.Pressure
+	case speed:
+		return s.Speed
+	case rainRate:
+		return s.RainRate
+	case length:
+		switch from {
+		case Mile, Km:
+			return s.Distance
+		case Foot, M:
+			return s.Height
+		}
+		return s.Rain
+	}
+	return ""
+}
+
+var floatType = reflect.TypeOf(weatherlink.Float{})
+
+// Convert converts every measurement in v, such as a *weatherlink.CurrentResponse or
+// *weatherlink.HistoricResponse, from the unit the API reports it in to the unit of sys.
+// v must be a pointer. Measurements are weatherlink.Float fields with a unit tag; fields
+// whose name already states the unit, such as rain_day_mm or rainfall_in, are left as they are.
+// The exception is rain rates: the inch per hour fields (rain_rate_hi_in, ...) are converted to
+// sys.RainRate like any other rate.
+func Convert(v interface{}, sys System) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("units: Convert needs a non-nil pointer, got %T", v)
+	}
+	return convert(rv, sys)
+}
+
+func convert(v reflect.Value, sys System) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return convert(v.Elem(), sys)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := convert(v.Index(i), sys); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := v.Field(i)
+			if !f.CanSet() {
+				continue
+			}
+			if f.Type() != floatType {
+				if err := convert(f, sys); err != nil {
+					return err
+				}
+				continue
+			}
+			from := Unit(t.Field(i).Tag.Get("unit"))
+			if from == "" {
+				continue
+			}
+			to := sys.target(from)
+			m := f.Addr().Interface().(*weatherlink.Float)
+			if to == "" || !m.Valid {
+				continue
+			}
+			c, err := ConvertValue(m.Value, from, to)
+			if err != nil {
+				return fmt.Errorf("units: field %v: %v", t.Field(i).Name, err)
+			}
+			m.Value = c
+		}
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+// Package units converts the imperial values reported by the WeatherLink API to other units.
+//
+// Quantities can be converted one at a time with the typed values:
+//
+//	units.Fahrenheit(75.6).Celsius()
+//	units.InchesOfMercury(29.95).Hectopascals()
+//
+// or whole responses can be converted in place with Convert.
+package units
+
+import "fmt"
+
+// Unit identifies a unit of measurement
+type Unit string
+
+// Units known to this package. The WeatherLink API reports values in DegF, InHg, Mph, Inch,
+// InchPerHour, Mile and Foot.
+const (
+	DegF Unit = "degF"
+	DegC Unit = "degC"
+	K    Unit = "K"
+
+	InHg Unit = "inHg"
+	MmHg Unit = "mmHg"
+	HPa  Unit = "hPa"
+	KPa  Unit = "kPa"
+	Pa   Unit = "Pa"
+
+	Mph  Unit = "mph"
+	Kmh  Unit = "km/h"
+	Ms   Unit = "m/s"
+	Knot Unit = "kn"
+
+	Inch Unit = "in"
+	Foot Unit = "ft"
+	Mile Unit = "mi"
+	Mm   Unit = "mm"
+	M    Unit = "m"
+	Km   Unit = "km"
+
+	InchPerHour Unit = "in/h"
+	MmPerHour   Unit = "mm/h"
+)
+
+// Temperature is a temperature in kelvin
+type Temperature float64
+
+// Fahrenheit returns a Temperature from degrees Fahrenheit
+func Fahrenheit(v float64) Temperature { return Temperature((v-32)*5/9 + 273.15) }
+
+// Celsius returns a Temperature from degrees Celsius
+func Celsius(v float64) Temperature { return Temperature(v + 273.15) }
+
+// Fahrenheit returns the temperature in degrees Fahrenheit
+func (t Temperature) Fahrenheit() float64 { return (float64(t)-273.15)*9/5 + 32 }
+
+// Celsius returns the temperature in degrees Celsius
+func (t Temperature) Celsius() float64 { return float64(t) - 273.15 }
+
+// Kelvin returns the temperature in kelvin
+func (t Temperature) Kelvin() float64 { return float64(t) }
+
+// Pressure is a pressure in pascals
+type Pressure float64
+
+const pascalsPerInHg = 3386.389
+const pascalsPerMmHg = 133.322387415
+
+// InchesOfMercury returns a Pressure from inches of mercury
+func InchesOfMercury(v float64) Pressure { return Pressure(v * pascalsPerInHg) }
+
+// Hectopascals returns a Pressure from hectopascals (millibars)
+func Hectopascals(v float64) Pressure { return Pressure(v * 100) }
+
+// InchesOfMercury returns the pressure in inches of mercury
+func (p Pressure) InchesOfMercury() float64 { return float64(p) / pascalsPerInHg }
+
+// MillimetresOfMercury returns the pressure in millimetres of mercury
+func (p Pressure) MillimetresOfMercury() float64 { return float64(p) / pascalsPerMmHg }
+
+// Hectopascals returns the pressure in hectopascals (millibars)
+func (p Pressure) Hectopascals() float64 { return float64(p) / 100 }
+
+// Kilopascals returns the pressure in kilopascals
+func (p Pressure) Kilopascals() float64 { return float64(p) / 1000 }
+
+// Pascals returns the pressure in pascals
+func (p Pressure) Pascals() float64 { return float64(p) }
+
+// Speed is a speed in metres per second
+type Speed float64
+
+const metresPerMile = 1609.344
+
+// MilesPerHour returns a Speed from miles per hour
+func MilesPerHour(v float64) Speed { return Speed(v * metresPerMile / 3600) }
+
+// KilometresPerHour returns a Speed from kilometres per hour
+func KilometresPerHour(v float64) Speed { return Speed(v / 3.6) }
+
+// MilesPerHour returns the speed in miles per hour
+func (s Speed) MilesPerHour() float64 { return float64(s) * 3600 / metresPerMile }
+
+// KilometresPerHour returns the speed in kilometres per hour
+func (s Speed) KilometresPerHour() float64 { return float64(s) * 3.6 }
+
+// MetresPerSecond returns the speed in metres per second
+func (s Speed) MetresPerSecond() float64 { return float64(s) }
+
+// Knots returns the speed in knots
+func (s Speed) Knots() float64 { return float64(s) * 3600 / 1852 }
+
+// Length is a length in metres
+type Length float64
+
+const metresPerInch = 0.0254
+const metresPerFoot = 0.3048
+
+// Inches returns a Length from inches
+func Inches(v float64) Length { return Length(v * metresPerInch) }
+
+// Feet returns a Length from feet
+func Feet(v float64) Length { return Length(v * metresPerFoot) }
+
+// Miles returns a Length from miles
+func Miles(v float64) Length { return Length(v * metresPerMile) }
+
+// Millimetres returns a Length from millimetres
+func Millimetres(v float64) Length { return Length(v / 1000) }
+
+// Inches returns the length in inches
+func (l Length) Inches() float64 { return float64(l) / metresPerInch }
+
+// Feet returns the length in feet
+func (l Length) Feet() float64 { return float64(l) / metresPerFoot }
+
+// Miles returns the length in miles
+func (l Length) Miles() float64 { return float64(l) / metresPerMile }
+
+// Millimetres returns the length in millimetres
+func (l Length) Millimetres() float64 { return float64(l) * 1000 }
+
+// Metres returns the length in metres
+func (l Length) Metres() float64 { return float64(l) }
+
+// Kilometres returns the length in kilometres
+func (l Length) Kilometres() float64 { return float64(l) / 1000 }
+
+// RainRate is a rain rate in millimetres per hour
+type RainRate float64
+
+// InchesPerHour returns a RainRate from inches per hour
+func InchesPerHour(v float64) RainRate { return RainRate(v * metresPerInch * 1000) }
+
+// MillimetresPerHour returns a RainRate from millimetres per hour
+func MillimetresPerHour(v float64) RainRate { return RainRate(v) }
+
+// InchesPerHour returns the rain rate in inches per hour
+func (r RainRate) InchesPerHour() float64 { return float64(r) / (metresPerInch * 1000) }
+
+// MillimetresPerHour returns the rain rate in millimetres per hour
+func (r RainRate) MillimetresPerHour() float64 { return float64(r) }
+
+type dimension int
+
+const (
+	temperature dimension = iota + 1
+	pressure
+	speed
+	length
+	rainRate
+)
+
+// unit converts between a unit and the base unit of its dimension
+type unit struct {
+	dim      dimension
+	toBase   func(float64) float64
+	fromBase func(float64) float64
+}
+
+var table = map[Unit]unit{
+	DegF: {temperature, func(v float64) float64 { return float64(Fahrenheit(v)) }, func(v float64) float64 { return Temperature(v).Fahrenheit() }},
+	DegC: {temperature, func(v float64) float64 { return float64(Celsius(v)) }, func(v float64) float64 { return Temperature(v).Celsius() }},
+	K:    {temperature, identity, identity},
+
+	InHg: {pressure, func(v float64) float64 { return float64(InchesOfMercury(v)) }, func(v float64) float64 { return Pressure(v).InchesOfMercury() }},
+	MmHg: {pressure, func(v float64) float64 { return v * pascalsPerMmHg }, func(v float64) float64 { return Pressure(v).MillimetresOfMercury() }},
+	HPa:  {pressure, func(v float64) float64 { return float64(Hectopascals(v)) }, func(v float64) float64 { return Pressure(v).Hectopascals() }},
+	KPa:  {pressure, func(v float64) float64 { return v * 1000 }, func(v float64) float64 { return Pressure(v).Kilopascals() }},
+	Pa:   {pressure, identity, identity},
+
+	Mph:  {speed, func(v float64) float64 { return float64(MilesPerHour(v)) }, func(v float64) float64 { return Speed(v).MilesPerHour() }},
+	Kmh:  {speed, func(v float64) float64 { return float64(KilometresPerHour(v)) }, func(v float64) float64 { return Speed(v).KilometresPerHour() }},
+	Ms:   {speed, identity, identity},
+	Knot: {speed, func(v float64) float64 { return v * 1852 / 3600 }, func(v float64) float64 { return Speed(v).Knots() }},
+
+	Inch: {length, func(v float64) float64 { return float64(Inches(v)) }, func(v float64) float64 { return Length(v).Inches() }},
+	Foot: {length, func(v float64) float64 { return float64(Feet(v)) }, func(v float64) float64 { return Length(v).Feet() }},
+	Mile: {length, func(v float64) float64 { return float64(Miles(v)) }, func(v float64) float64 { return Length(v).Miles() }},
+	Mm:   {length, func(v float64) float64 { return float64(Millimetres(v)) }, func(v float64) float64 { return Length(v).Millimetres() }},
+	M:    {length, identity, identity},
+	Km:   {length, func(v float64) float64 { return v * 1000 }, func(v float64) float64 { return Length(v).Kilometres() }},
+
+	InchPerHour: {rainRate, func(v float64) float64 { return float64(InchesPerHour(v)) }, func(v float64) float64 { return RainRate(v).InchesPerHour() }},
+	MmPerHour:   {rainRate, identity, identity},
+}
+
+func identity(v float64) float64 { return v }
+
+// ConvertValue converts v from one unit to another of the same kind
+func ConvertValue(v float64, from Unit, to Unit) (float64, error) {
+	f, ok := table[from]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", from)
+	}
+	t, ok := table[to]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", to)
+	}
+	if f.dim != t.dim {
+		return 0, fmt.Errorf("cannot convert %v to %v", from, to)
+	}
+	if from == to {
+		return v, nil
+	}
+	return t.fromBase(f.toBase(v)), nil
+}
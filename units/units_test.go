@@ -0,0 +1,180 @@
+package units
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexhowarth/go-weatherlink"
+	"github.com/alexhowarth/go-weatherlink/aggregate"
+)
+
+func TestConvertValue(t *testing.T) {
+
+	tests := []struct {
+		v      float64
+		from   Unit
+		to     Unit
+		expect float64
+	}{
+		{32, DegF, DegC, 0},
+		{212, DegF, K, 373.15},
+		{-40, DegC, DegF, -40},
+		{29.92, InHg, HPa, 1013.208},
+		{1013.25, HPa, InHg, 29.921},
+		{10, Mph, Kmh, 16.0934},
+		{10, Mph, Ms, 4.4704},
+		{10, Kmh, Knot, 5.3996},
+		{1, Inch, Mm, 25.4},
+		{1, Mile, Km, 1.609344},
+		{100, Foot, M, 30.48},
+		{0.1, InchPerHour, MmPerHour, 2.54},
+	}
+
+	for _, tt := range tests {
+		got, err := ConvertValue(tt.v, tt.from, tt.to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(got-tt.expect) > 0.001 {
+			t.Fatalf("%v %v to %v: expected %v got %v", tt.v, tt.from, tt.to, tt.expect, got)
+		}
+	}
+
+	if _, err := ConvertValue(1, DegF, Mm); err == nil {
+		t.Fatalf("Expected error converting temperature to length")
+	}
+	if _, err := ConvertValue(1, "furlong", Mm); err == nil {
+		t.Fatalf("Expected error for unknown unit")
+	}
+}
+
+func TestQuantities(t *testing.T) {
+
+	if got := Fahrenheit(75.6).Celsius(); math.Abs(got-24.222) > 0.001 {
+		t.Fatalf("Expected %v got %v", 24.222, got)
+	}
+	if got := Celsius(100).Fahrenheit(); math.Abs(got-212) > 0.001 {
+		t.Fatalf("Expected %v got %v", 212, got)
+	}
+	if got := InchesOfMercury(29.95).Hectopascals(); math.Abs(got-1014.22) > 0.01 {
+		t.Fatalf("Expected %v got %v", 1014.22, got)
+	}
+	if got := MilesPerHour(18).KilometresPerHour(); math.Abs(got-28.968) > 0.001 {
+		t.Fatalf("Expected %v got %v", 28.968, got)
+	}
+	if got := Inches(0.01).Millimetres(); math.Abs(got-0.254) > 0.0001 {
+		t.Fatalf("Expected %v got %v", 0.254, got)
+	}
+	if got := InchesPerHour(1).MillimetresPerHour(); math.Abs(got-25.4) > 0.0001 {
+		t.Fatalf("Expected %v got %v", 25.4, got)
+	}
+}
+
+func TestConvert(t *testing.T) {
+
+	b, err := ioutil.ReadFile(filepath.Join("..", "testdata", "current.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cr weatherlink.CurrentResponse
+	if err := json.Unmarshal(b, &cr); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Convert(&cr, Metric); err != nil {
+		t.Fatal(err)
+	}
+
+	d := cr.Sensors[0].Data[0].(*weatherlink.VantageCurrent)
+	if math.Abs(d.TempOut.Value-24.222) > 0.001 {
+		t.Fatalf("Expected %v got %v", 24.222, d.TempOut)
+	}
+	if math.Abs(d.Bar.Value-1014.22) > 0.01 {
+		t.Fatalf("Expected %v got %v", 1014.22, d.Bar)
+	}
+	if math.Abs(d.WindSpeed.Value-28.968) > 0.001 {
+		t.Fatalf("Expected %v got %v", 28.968, d.WindSpeed)
+	}
+	// not a measurement
+	if d.WindDir.Value != 216 {
+		t.Fatalf("Expected %v got %v", 216, d.WindDir)
+	}
+	// the unit is in the name
+	if d.RainDayIn.Value != 0.01 {
+		t.Fatalf("Expected %v got %v", 0.01, d.RainDayIn)
+	}
+	// missing values stay missing
+	if d.TempExtra1.Valid {
+		t.Fatalf("Expected missing value got %v", d.TempExtra1)
+	}
+
+	if err := Convert(cr, Metric); err == nil {
+		t.Fatalf("Expected error for non-pointer")
+	}
+}
+
+func TestConvertRainRate(t *testing.T) {
+
+	d := &weatherlink.VantageArchive{
+		RainRateHiIn: weatherlink.NewFloat(0.5),
+		RainRateHiMm: weatherlink.NewFloat(12.7),
+		RainfallIn:   weatherlink.NewFloat(0.1),
+	}
+	s := &aggregate.Summary{RainRateHi: weatherlink.NewFloat(0.5)}
+
+	if err := Convert(d, Metric); err != nil {
+		t.Fatal(err)
+	}
+	if err := Convert(s, Metric); err != nil {
+		t.Fatal(err)
+	}
+
+	{
+		expect := 12.7
+		got := d.RainRateHiIn.Value
+		if math.Abs(got-expect) > 1e-9 {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := 12.7
+		got := s.RainRateHi.Value
+		if math.Abs(got-expect) > 1e-9 {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	// millimetre rates and rainfall in inches are left as they are
+	{
+		expect := 12.7
+		got := d.RainRateHiMm.Value
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := 0.1
+		got := d.RainfallIn.Value
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
+
+func TestParseSystem(t *testing.T) {
+
+	for name, expect := range map[string]System{"metric": Metric, "Imperial": Imperial, "si": SI} {
+		got, err := ParseSystem(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	if _, err := ParseSystem("nautical"); err == nil {
+		t.Fatalf("Expected error for unknown system")
+	}
+}
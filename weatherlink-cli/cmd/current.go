@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/alexhowarth/go-weatherlink/influx"
 	"github.com/spf13/cobra"
 )
 
@@ -11,17 +12,29 @@ var currentCmd = &cobra.Command{
 	Use:   "current",
 	Short: "Current weather",
 	Run: func(cmd *cobra.Command, args []string) {
-		resp, err := client.CurrentGeneric(station)
+		checkFormat("json", "influx")
+		// always decode into a CurrentResponse, so the JSON has one shape with or without
+		// --units; records are written back with the fields they were received with
+		resp, err := client.Current(station)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
+		if unitSystem != "" {
+			convertUnits(&resp)
+		}
+		if format == "influx" {
+			printInflux(func(e *influx.Encoder) error { return e.EncodeCurrent(resp) })
+			return
+		}
 		printJSON(resp)
 	},
 }
 
 func init() {
 	currentCmd.Flags().IntVar(&station, "station", 0, "numeric station id")
+	currentCmd.Flags().StringVar(&unitSystem, "units", "", "convert values to metric, imperial or si")
+	addFormatFlags(currentCmd, "json", "influx")
 	currentCmd.MarkFlagRequired("station")
 	rootCmd.AddCommand(currentCmd)
 }
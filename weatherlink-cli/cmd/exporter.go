@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/alexhowarth/go-weatherlink/exporter"
+	"github.com/spf13/cobra"
+)
+
+var listen string
+var exporterStations []int
+var interval time.Duration
+
+var exporterCmd = &cobra.Command{
+	Use:   "exporter",
+	Short: "Prometheus exporter for current conditions",
+	Long:  `Poll current conditions for one or more stations and serve them as Prometheus metrics on /metrics.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		e := exporter.New(client, exporterStations)
+		e.Interval = interval
+		go e.Run(context.Background())
+
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", e)
+		if err := http.ListenAndServe(listen, mux); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	exporterCmd.Flags().StringVar(&listen, "listen", ":9813", "address to serve metrics on")
+	exporterCmd.Flags().IntSliceVar(&exporterStations, "station", nil, "numeric station id (repeat or comma separate for several)")
+	exporterCmd.Flags().DurationVar(&interval, "interval", exporter.DefaultInterval, "time between polls")
+	exporterCmd.MarkFlagRequired("station")
+	rootCmd.AddCommand(exporterCmd)
+}
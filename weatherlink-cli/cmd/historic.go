@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
 
+	"github.com/alexhowarth/go-weatherlink"
+	"github.com/alexhowarth/go-weatherlink/influx"
 	"github.com/spf13/cobra"
 )
 
@@ -14,6 +17,7 @@ type historicTime struct {
 
 var start historicTime
 var end historicTime
+var day string
 
 func (h *historicTime) Set(s string) error {
 	t, err := time.Parse(time.RFC3339, s)
@@ -32,16 +36,97 @@ func (h *historicTime) Type() string {
 	return "string"
 }
 
+// findStation returns a station's metadata, failing if the API does not return it
+func findStation(station int) (weatherlink.Station, error) {
+	sr, err := client.Stations([]int{station})
+	if err != nil {
+		return weatherlink.Station{}, err
+	}
+	if len(sr.Stations) == 0 {
+		return weatherlink.Station{}, fmt.Errorf("station %d not found", station)
+	}
+	return sr.Stations[0], nil
+}
+
+// stationLocation returns a station's time zone, failing if the station is not found or its
+// time zone is not known
+func stationLocation(station int) (*time.Location, error) {
+	s, err := findStation(station)
+	if err != nil {
+		return nil, err
+	}
+	return s.LoadLocation()
+}
+
+// stationDay returns the start and end of a day (YYYY-MM-DD, today or yesterday) in the
+// station's time zone
+func stationDay(station int, day string) (time.Time, time.Time, error) {
+	s, err := findStation(station)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	loc, err := s.LoadLocation()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	var t time.Time
+	switch day {
+	case "today":
+		t = time.Now()
+	case "yesterday":
+		t = time.Now().In(loc).AddDate(0, 0, -1)
+	default:
+		t, err = time.ParseInLocation("2006-01-02", day, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	start, end := s.Day(t)
+	return start, end, nil
+}
+
 var historicCmd = &cobra.Command{
 	Use:   "historic",
 	Short: "Historic weather",
-	Long:  `Provide a start and end time in RFC3339 format with a span no greater than 24 hours`,
+	Long: `Provide a start and end time in RFC3339 format, or a day (YYYY-MM-DD, today or yesterday)
+in the station's time zone. Spans greater than 24 hours are fetched in several requests.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		resp, err := client.HistoricGeneric(station, start.t, end.t)
+		if day != "" {
+			if !start.t.IsZero() || !end.t.IsZero() {
+				fmt.Println("provide either --day or --start and --end, not both")
+				os.Exit(1)
+			}
+			var err error
+			start.t, end.t, err = stationDay(station, day)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		} else if start.t.IsZero() || end.t.IsZero() {
+			fmt.Println("provide --start and --end, or --day")
+			os.Exit(1)
+		}
+		checkFormat("json", "influx", "csv", "tsv")
+		if format == "csv" || format == "tsv" {
+			printTable()
+			return
+		}
+		// always decode into a HistoricResponse, so the output has the same shape however
+		// many requests the span takes; records are written back with the fields they were
+		// received with, including those the types do not model
+		resp, err := client.HistoricRange(context.Background(), station, start.t, end.t)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
+		if unitSystem != "" {
+			convertUnits(&resp)
+		}
+		if format == "influx" {
+			printInflux(func(e *influx.Encoder) error { return e.EncodeHistoric(resp) })
+			return
+		}
 		printJSON(resp)
 	},
 }
@@ -50,8 +135,10 @@ func init() {
 	historicCmd.Flags().IntVar(&station, "station", 0, "numeric station id")
 	historicCmd.Flags().Var(&start, "start", "start date (RFC3339)")
 	historicCmd.Flags().Var(&end, "end", "end date (RFC3339)")
+	historicCmd.Flags().StringVar(&day, "day", "", "day in the station's time zone (YYYY-MM-DD, today or yesterday)")
+	historicCmd.Flags().StringVar(&unitSystem, "units", "", "convert values to metric, imperial or si")
+	addFormatFlags(historicCmd, "json", "influx", "csv", "tsv")
+	addTableFlags(historicCmd)
 	historicCmd.MarkFlagRequired("station")
-	historicCmd.MarkFlagRequired("start")
-	historicCmd.MarkFlagRequired("end")
 	rootCmd.AddCommand(historicCmd)
 }
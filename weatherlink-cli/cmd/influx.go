@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/alexhowarth/go-weatherlink/influx"
+	"github.com/spf13/cobra"
+)
+
+var format string
+var influxWriter influx.Writer
+
+// addFormatFlags adds the output format flags to a command supporting formats
+func addFormatFlags(cmd *cobra.Command, formats ...string) {
+	cmd.Flags().StringVar(&format, "format", "json", "output format: "+strings.Join(formats, ", "))
+	cmd.Flags().StringVar(&influxWriter.URL, "influx-url", "", "write influx output to this InfluxDB v2 server instead of stdout")
+	cmd.Flags().StringVar(&influxWriter.Token, "influx-token", "", "InfluxDB api token")
+	cmd.Flags().StringVar(&influxWriter.Org, "influx-org", "", "InfluxDB organisation")
+	cmd.Flags().StringVar(&influxWriter.Bucket, "influx-bucket", "", "InfluxDB bucket")
+}
+
+// checkFormat exits if the output format is not one of formats
+func checkFormat(formats ...string) {
+	for _, f := range formats {
+		if format == f {
+			return
+		}
+	}
+	fmt.Printf("unknown format %q\n", format)
+	os.Exit(1)
+}
+
+// printInflux encodes records as line protocol and prints them, or writes them to InfluxDB
+// if --influx-url is set
+func printInflux(encode func(e *influx.Encoder) error) {
+	sr, err := client.AllSensors()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	var buf bytes.Buffer
+	e := influx.NewEncoder(&buf)
+	e.SetSensors(sr.Sensors)
+	if err := encode(e); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if influxWriter.URL == "" {
+		os.Stdout.Write(buf.Bytes())
+		return
+	}
+	if err := influxWriter.Write(context.Background(), buf.Bytes()); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var nodes []int
+
+var nodesCmd = &cobra.Command{
+	Use:   "nodes",
+	Short: "List nodes (gateways)",
+	Run: func(cmd *cobra.Command, args []string) {
+		var resp interface{}
+		var err error
+		if len(nodes) > 0 {
+			resp, err = client.NodesGeneric(nodes)
+		} else {
+			resp, err = client.AllNodesGeneric()
+		}
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		printJSON(resp)
+	},
+}
+
+func init() {
+	nodesCmd.Flags().IntSliceVar(&nodes, "id", []int{}, "node ids")
+	rootCmd.AddCommand(nodesCmd)
+}
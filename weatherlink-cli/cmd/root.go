@@ -6,12 +6,15 @@ import (
 	"os"
 
 	"github.com/alexhowarth/go-weatherlink"
+	"github.com/alexhowarth/go-weatherlink/units"
 	"github.com/spf13/cobra"
 )
 
 var key string
 var secret string
+var baseURL string
 var station int
+var unitSystem string
 var client *weatherlink.Client
 
 var rootCmd = &cobra.Command{
@@ -30,6 +33,7 @@ func init() {
 	cobra.OnInitialize(initConfig)
 	rootCmd.PersistentFlags().StringVar(&key, "key", "", "api key")
 	rootCmd.PersistentFlags().StringVar(&secret, "secret", "", "api secret")
+	rootCmd.PersistentFlags().StringVar(&baseURL, "base-url", "", "api base url (default "+weatherlink.DefaultBaseURL+")")
 	rootCmd.MarkPersistentFlagRequired("key")
 	rootCmd.MarkPersistentFlagRequired("secret")
 	rootCmd.SetHelpCommand(&cobra.Command{
@@ -40,8 +44,9 @@ func init() {
 
 func initConfig() {
 	config := &weatherlink.Config{
-		Key:    key,
-		Secret: secret,
+		Key:     key,
+		Secret:  secret,
+		BaseURL: baseURL,
 	}
 	client = config.NewClient()
 }
@@ -53,3 +58,16 @@ func printJSON(data interface{}) {
 	}
 	fmt.Println(string(b))
 }
+
+// convertUnits converts a typed response to the unit system given by --units
+func convertUnits(v interface{}) {
+	sys, err := units.ParseSystem(unitSystem)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := units.Convert(v, sys); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
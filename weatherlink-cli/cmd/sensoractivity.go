@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var activitySensors []int
+
+type sensorActivity struct {
+	Lsid         int    `json:"lsid"`
+	ProductName  string `json:"product_name"`
+	TimeReceived int64  `json:"time_received"`
+	TimeRecorded int64  `json:"time_recorded"`
+}
+
+var sensoractivityCmd = &cobra.Command{
+	Use:   "sensoractivity",
+	Short: "Displays when sensor data was last received",
+	Run: func(cmd *cobra.Command, args []string) {
+		var ids []int
+		if len(activitySensors) > 0 {
+			ids = activitySensors
+		}
+		activity, err := client.SensorActivity(ids)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		sensors, err := client.Sensors(ids)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		names := make(map[int]string)
+		for _, s := range sensors.Sensors {
+			names[s.Lsid] = s.ProductName
+		}
+
+		out := make([]sensorActivity, 0, len(activity.SensorActivity))
+		for _, a := range activity.SensorActivity {
+			out = append(out, sensorActivity{
+				Lsid:         a.Lsid,
+				ProductName:  names[a.Lsid],
+				TimeReceived: a.TimeReceived,
+				TimeRecorded: a.TimeRecorded,
+			})
+		}
+		printJSON(out)
+	},
+}
+
+func init() {
+	sensoractivityCmd.Flags().IntSliceVar(&activitySensors, "id", []int{}, "sensor ids")
+	rootCmd.AddCommand(sensoractivityCmd)
+}
@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/spf13/cobra"
 )
 
@@ -10,7 +13,10 @@ var sensorcatalogCmd = &cobra.Command{
 	Use:   "sensorcatalog",
 	Short: "Downloads the sensor catalogue",
 	Run: func(cmd *cobra.Command, args []string) {
-		client.SensorCatalog(sensorPath)
+		if err := client.SaveSensorCatalog(sensorPath); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
 	},
 }
 
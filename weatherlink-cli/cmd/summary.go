@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/alexhowarth/go-weatherlink/aggregate"
+	"github.com/spf13/cobra"
+)
+
+var period string
+
+var summaryCmd = &cobra.Command{
+	Use:   "summary",
+	Short: "Summarise historic weather",
+	Long:  `Summarise the historic records between start and end (RFC3339) by day, month or year in the station's time zone.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		p, ok := aggregate.ParsePeriod(period)
+		if !ok {
+			fmt.Printf("unknown period %q\n", period)
+			os.Exit(1)
+		}
+
+		loc, err := stationLocation(station)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		a := aggregate.New(loc, p)
+		it := client.IterateHistoric(context.Background(), station, start.t, end.t)
+		defer it.Close()
+		for it.Next() {
+			a.Add(it.Record())
+		}
+		if err := it.Err(); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		summaries := a.Summaries()
+		if unitSystem != "" {
+			convertUnits(&summaries)
+		}
+		printJSON(summaries)
+	},
+}
+
+func init() {
+	summaryCmd.Flags().IntVar(&station, "station", 0, "numeric station id")
+	summaryCmd.Flags().Var(&start, "start", "start date (RFC3339)")
+	summaryCmd.Flags().Var(&end, "end", "end date (RFC3339)")
+	summaryCmd.Flags().StringVar(&period, "period", "day", "summarise by day, month or year")
+	summaryCmd.Flags().StringVar(&unitSystem, "units", "", "convert values to metric, imperial or si")
+	summaryCmd.MarkFlagRequired("station")
+	summaryCmd.MarkFlagRequired("start")
+	summaryCmd.MarkFlagRequired("end")
+	rootCmd.AddCommand(summaryCmd)
+}
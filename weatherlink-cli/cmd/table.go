@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/alexhowarth/go-weatherlink"
+	"github.com/alexhowarth/go-weatherlink/table"
+	"github.com/spf13/cobra"
+)
+
+var columns []string
+var timeFormat string
+var null string
+
+// addTableFlags adds the csv and tsv output flags to a command
+func addTableFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&columns, "columns", nil, "csv/tsv columns, e.g. ts,temp_out,bar (default ts, ids and every measurement)")
+	cmd.Flags().StringVar(&timeFormat, "time-format", "unix", "csv/tsv timestamp format: unix, rfc3339 or local (the station's time zone)")
+	cmd.Flags().StringVar(&null, "null", "", "csv/tsv text for missing values (default an empty cell)")
+}
+
+// printTable streams the historic records between start and end as csv or tsv
+func printTable() {
+	tf, err := table.ParseTimeFormat(timeFormat)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	opts := table.Options{
+		Columns:    columns,
+		TimeFormat: tf,
+		Null:       null,
+	}
+	if format == "tsv" {
+		opts.Comma = '\t'
+	}
+	if tf == table.Local {
+		loc, err := stationLocation(station)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		opts.Location = loc
+	}
+
+	var w *table.Writer
+	write := func(r weatherlink.HistoricRecord) {
+		if err := w.Write(r); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	// without --columns, hold back the records of the first request so the header has the
+	// measurements of every sensor
+	var pending []weatherlink.HistoricRecord
+	begin := func() {
+		if len(opts.Columns) == 0 {
+			opts.Columns = table.DefaultColumns(pending)
+		}
+		w = table.NewWriter(os.Stdout, opts)
+		for _, p := range pending {
+			write(p)
+		}
+		pending = nil
+	}
+	if len(opts.Columns) > 0 {
+		begin()
+	}
+	first := start.t.Add(weatherlink.MaxHistoricSpan)
+
+	it := client.IterateHistoric(context.Background(), station, start.t, end.t)
+	defer it.Close()
+	for it.Next() {
+		r := it.Record()
+		if unitSystem != "" {
+			convertUnits(r.Data)
+		}
+		if w == nil {
+			if !r.Time.After(first) {
+				pending = append(pending, r)
+				continue
+			}
+			begin()
+		}
+		write(r)
+	}
+	if err := it.Err(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if w == nil {
+		begin()
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
@@ -2,12 +2,15 @@
 package weatherlink
 
 import (
+	"bytes"
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 	"net/http"
 	"net/http/httputil"
@@ -23,6 +26,9 @@ import (
 const apiHost string = "api.weatherlink.com"
 const apiVersion string = "v2"
 
+// DefaultBaseURL is the WeatherLink v2 API location used when Config.BaseURL is empty
+const DefaultBaseURL string = "https://" + apiHost + "/" + apiVersion
+
 const (
 	keyParam    string = "api-key"
 	secretParam string = "api-secret"
@@ -33,22 +39,36 @@ const (
 const (
 	stationsPathFmt   string = "/stations/%v"
 	sensorsPathFmt    string = "/sensors/%v"
+	nodesPathFmt      string = "/nodes/%v"
+	activityPathFmt   string = "/sensor-activity/%v"
 	sensorCatalogPath string = "/sensor-catalog"
 	currentPathFmt    string = "/current/%v"
 	historicPathFmt   string = "/historic/%v?start-timestamp=%v&end-timestamp=%v"
 )
 
-// Config contains the fields to construct a Client. Client is optional.
+// Config contains the fields to construct a Client. Only Key and Secret are required.
+// BaseURL is the scheme, host and optional path prefix of the API (DefaultBaseURL if empty).
+// Retry enables retrying of transient failures (no retries if nil).
+// Limiter throttles every request, including retries (no limit if nil).
+// HistoricConcurrency bounds the concurrent requests made by HistoricRange (one at a time if 0).
+// Cache stores successful responses for the durations in CacheTTL (no caching if nil).
 type Config struct {
-	Client *http.Client
-	Key    string
-	Secret string
+	Client              *http.Client
+	Key                 string
+	Secret              string
+	BaseURL             string
+	Retry               *RetryPolicy
+	Limiter             *Limiter
+	HistoricConcurrency int
+	Cache               Cache
+	CacheTTL            CacheTTL
 }
 
 // Client contains the http client and config. It is used to make requests to the API endpoints
 type Client struct {
-	Client *http.Client
-	Config *Config
+	Client  *http.Client
+	Config  *Config
+	baseURL *url.URL
 }
 
 type SignatureParams map[string]string
@@ -58,9 +78,18 @@ func (c *Config) NewClient() *Client {
 	if c.Key == "" || c.Secret == "" {
 		panic("Key and Secret required.")
 	}
+	base := DefaultBaseURL
+	if c.BaseURL != "" {
+		base = c.BaseURL
+	}
+	u, err := url.Parse(base)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		panic("BaseURL must include a scheme and host.")
+	}
 	wl := &Client{
-		Client: &http.Client{},
-		Config: c,
+		Client:  &http.Client{},
+		Config:  c,
+		baseURL: u,
 	}
 	if c.Client != nil {
 		wl.Client = c.Client
@@ -87,9 +116,9 @@ func (w *Client) buildURL(s string, p SignatureParams) string {
 		panic("Path required.")
 	}
 
-	u.Scheme = "https"
-	u.Host = apiHost
-	u.Path = path.Join(apiVersion + u.Path)
+	u.Scheme = w.baseURL.Scheme
+	u.Host = w.baseURL.Host
+	u.Path = path.Join("/", w.baseURL.Path, u.Path)
 
 	q := u.Query()
 	for k := range q {
@@ -131,11 +160,66 @@ func (s SignatureParams) String() string {
 	return buf.String()
 }
 
-func (w *Client) get(url string, params SignatureParams) (*http.Response, error) {
+func (w *Client) get(ctx context.Context, rawurl string, params SignatureParams) (*http.Response, error) {
 	if params == nil {
 		params = w.MakeSignatureParams()
 	}
-	return w.Client.Get(w.buildURL(url, params))
+	cache := w.Config.Cache
+	if cache == nil {
+		return w.do(ctx, rawurl, params)
+	}
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	expires, ok := w.Config.CacheTTL.expires(rawurl, u.Query(), time.Now())
+	if !ok {
+		return w.do(ctx, rawurl, params)
+	}
+	key := w.cacheKey(u, params)
+	if body, ok := cache.Get(key); ok {
+		return cached(body), nil
+	}
+	resp, err := w.do(ctx, rawurl, params)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	cache.Set(key, body, expires)
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// do makes the request, retrying as set by the RetryPolicy
+func (w *Client) do(ctx context.Context, url string, params SignatureParams) (*http.Response, error) {
+	retry := w.Config.Retry
+	for attempt := 0; ; attempt++ {
+		if w.Config.Limiter != nil {
+			if err := w.Config.Limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+		// a stale timestamp is rejected, so sign each attempt afresh once
+		// any wait for the limiter is over
+		params = w.resign(params)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.buildURL(url, params), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := w.Client.Do(req)
+		if retry == nil || attempt+1 >= retry.MaxAttempts || !retry.retryable(ctx, resp, err) {
+			return resp, err
+		}
+		d := retry.delay(attempt, resp)
+		discard(resp)
+		if err := sleep(ctx, d); err != nil {
+			return nil, err
+		}
+	}
 }
 
 // encode returns an hexadecimal HMAC string (used for the signature)
@@ -147,31 +231,38 @@ func encode(secret string, msg string) string {
 
 // StationsResponse represents data from the /stations endpoint
 type StationsResponse struct {
-	Stations []struct {
-		StationID           int     `json:"station_id"`
-		StationName         string  `json:"station_name"`
-		GatewayID           int     `json:"gateway_id"`
-		GatewayIDHex        string  `json:"gateway_id_hex"`
-		ProductNumber       string  `json:"product_number"`
-		Username            string  `json:"username"`
-		UserEmail           string  `json:"user_email"`
-		CompanyName         string  `json:"company_name"`
-		Active              bool    `json:"active"`
-		Private             bool    `json:"private"`
-		RecordingInterval   int     `json:"recording_interval"`
-		FirmwareVersion     string  `json:"firmware_version"`
-		Meid                string  `json:"meid"`
-		RegisteredDate      int     `json:"registered_date"`
-		SubscriptionEndDate int     `json:"subscription_end_date"`
-		TimeZone            string  `json:"time_zone"`
-		City                string  `json:"city"`
-		Region              string  `json:"region"`
-		Country             string  `json:"country"`
-		Latitude            float64 `json:"latitude"`
-		Longitude           float64 `json:"longitude"`
-		Elevation           float64 `json:"elevation"`
-	} `json:"stations"`
-	GeneratedAt int `json:"generated_at"`
+	Stations    []Station       `json:"stations"`
+	GeneratedAt int             `json:"generated_at"`
+	Raw         json.RawMessage `json:"-"` // the response as received
+}
+
+// Station is one weather station from the /stations endpoint
+type Station struct {
+	StationID           int     `json:"station_id"`
+	StationName         string  `json:"station_name"`
+	GatewayID           int     `json:"gateway_id"`
+	GatewayIDHex        string  `json:"gateway_id_hex"`
+	ProductNumber       string  `json:"product_number"`
+	Username            string  `json:"username"`
+	UserEmail           string  `json:"user_email"`
+	CompanyName         string  `json:"company_name"`
+	Active              bool    `json:"active"`
+	Private             bool    `json:"private"`
+	RecordingInterval   int     `json:"recording_interval"`
+	FirmwareVersion     string  `json:"firmware_version"`
+	Meid                string  `json:"meid"`
+	RegisteredDate      int     `json:"registered_date"`
+	SubscriptionEndDate int     `json:"subscription_end_date"`
+	TimeZone            string  `json:"time_zone"`
+	City                string  `json:"city"`
+	Region              string  `json:"region"`
+	Country             string  `json:"country"`
+	Latitude            float64 `json:"latitude"`
+	Longitude           float64 `json:"longitude"`
+	Elevation           float64 `json:"elevation"`
+
+	Extra  map[string]json.RawMessage `json:"-"` // fields not known to this package
+	absent map[string]bool            // fields missing from the JSON decoded
 }
 
 // AllStations gets all weather stations associated with your API Key
@@ -179,14 +270,29 @@ func (w *Client) AllStations() (sr StationsResponse, err error) {
 	return w.Stations(nil)
 }
 
+// AllStationsContext is like AllStations but uses ctx for the request
+func (w *Client) AllStationsContext(ctx context.Context) (sr StationsResponse, err error) {
+	return w.StationsContext(ctx, nil)
+}
+
 // AllStationsGeneric gets all weather stations associated with your API Key
 // The result is an interface{} for generic use
 func (w *Client) AllStationsGeneric() (sr interface{}, err error) {
 	return w.StationsGeneric(nil)
 }
 
+// AllStationsGenericContext is like AllStationsGeneric but uses ctx for the request
+func (w *Client) AllStationsGenericContext(ctx context.Context) (sr interface{}, err error) {
+	return w.StationsGenericContext(ctx, nil)
+}
+
 // Stations gets weather stations for one or more station IDs provided
 func (w *Client) Stations(stations []int) (sr StationsResponse, err error) {
+	return w.StationsContext(context.Background(), stations)
+}
+
+// StationsContext is like Stations but uses ctx for the request
+func (w *Client) StationsContext(ctx context.Context, stations []int) (sr StationsResponse, err error) {
 
 	csv := intArrToCSV(stations)
 
@@ -195,13 +301,14 @@ func (w *Client) Stations(stations []int) (sr StationsResponse, err error) {
 		sp.Add("station-ids", csv)
 	}
 
-	resp, err := w.get(fmt.Sprintf(stationsPathFmt, csv), sp)
+	resp, err := w.get(ctx, fmt.Sprintf(stationsPathFmt, csv), sp)
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		err = fmt.Errorf("Error making Stations request. Got status: %v", resp.Status)
+		err = newAPIError("Stations", resp, stations, nil)
 		return
 	}
 
@@ -216,6 +323,11 @@ func (w *Client) Stations(stations []int) (sr StationsResponse, err error) {
 // StationsGeneric gets weather stations for one or more station IDs provided
 // The result is an interface{} for generic use
 func (w *Client) StationsGeneric(stations []int) (sr interface{}, err error) {
+	return w.StationsGenericContext(context.Background(), stations)
+}
+
+// StationsGenericContext is like StationsGeneric but uses ctx for the request
+func (w *Client) StationsGenericContext(ctx context.Context, stations []int) (sr interface{}, err error) {
 
 	csv := intArrToCSV(stations)
 
@@ -224,13 +336,14 @@ func (w *Client) StationsGeneric(stations []int) (sr interface{}, err error) {
 		sp.Add("station-ids", csv)
 	}
 
-	resp, err := w.get(fmt.Sprintf(stationsPathFmt, csv), sp)
+	resp, err := w.get(ctx, fmt.Sprintf(stationsPathFmt, csv), sp)
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		err = fmt.Errorf("Error making Stations request. Got status: %v", resp.Status)
+		err = newAPIError("Stations", resp, stations, nil)
 		return
 	}
 
@@ -244,30 +357,37 @@ func (w *Client) StationsGeneric(stations []int) (sr interface{}, err error) {
 
 // SensorsResponse represents data from the /sensors endpoint
 type SensorsResponse struct {
-	Sensors []struct {
-		Lsid              int         `json:"lsid"`
-		SensorType        int         `json:"sensor_type"`
-		Category          string      `json:"category"`
-		Manufacturer      string      `json:"manufacturer"`
-		ProductName       string      `json:"product_name"`
-		ProductNumber     string      `json:"product_number"`
-		RainCollectorType int         `json:"rain_collector_type"`
-		Active            bool        `json:"active"`
-		CreatedDate       int         `json:"created_date"`
-		ModifiedDate      int         `json:"modified_date"`
-		StationID         int         `json:"station_id"`
-		StationName       string      `json:"station_name"`
-		ParentDeviceType  string      `json:"parent_device_type"`
-		ParentDeviceName  string      `json:"parent_device_name"`
-		ParentDeviceID    int         `json:"parent_device_id"`
-		ParentDeviceIDHex string      `json:"parent_device_id_hex"`
-		PortNumber        int         `json:"port_number"`
-		Latitude          float64     `json:"latitude"`
-		Longitude         float64     `json:"longitude"`
-		Elevation         float64     `json:"elevation"`
-		TxID              interface{} `json:"tx_id"`
-	} `json:"sensors"`
-	GeneratedAt int `json:"generated_at"`
+	Sensors     []Sensor        `json:"sensors"`
+	GeneratedAt int             `json:"generated_at"`
+	Raw         json.RawMessage `json:"-"` // the response as received
+}
+
+// Sensor is one sensor from the /sensors endpoint
+type Sensor struct {
+	Lsid              int         `json:"lsid"`
+	SensorType        int         `json:"sensor_type"`
+	Category          string      `json:"category"`
+	Manufacturer      string      `json:"manufacturer"`
+	ProductName       string      `json:"product_name"`
+	ProductNumber     string      `json:"product_number"`
+	RainCollectorType int         `json:"rain_collector_type"`
+	Active            bool        `json:"active"`
+	CreatedDate       int         `json:"created_date"`
+	ModifiedDate      int         `json:"modified_date"`
+	StationID         int         `json:"station_id"`
+	StationName       string      `json:"station_name"`
+	ParentDeviceType  string      `json:"parent_device_type"`
+	ParentDeviceName  string      `json:"parent_device_name"`
+	ParentDeviceID    int         `json:"parent_device_id"`
+	ParentDeviceIDHex string      `json:"parent_device_id_hex"`
+	PortNumber        int         `json:"port_number"`
+	Latitude          float64     `json:"latitude"`
+	Longitude         float64     `json:"longitude"`
+	Elevation         float64     `json:"elevation"`
+	TxID              interface{} `json:"tx_id"`
+
+	Extra  map[string]json.RawMessage `json:"-"` // fields not known to this package
+	absent map[string]bool            // fields missing from the JSON decoded
 }
 
 // AllSensors gets all sensors attached to all weather stations associated with your API Key
@@ -275,14 +395,29 @@ func (w *Client) AllSensors() (sr SensorsResponse, err error) {
 	return w.Sensors(nil)
 }
 
+// AllSensorsContext is like AllSensors but uses ctx for the request
+func (w *Client) AllSensorsContext(ctx context.Context) (sr SensorsResponse, err error) {
+	return w.SensorsContext(ctx, nil)
+}
+
 // AllSensors gets all sensors attached to all weather stations associated with your API Key
 // The result is an interface{} for generic use
 func (w *Client) AllSensorsGeneric() (sr interface{}, err error) {
 	return w.SensorsGeneric(nil)
 }
 
+// AllSensorsGenericContext is like AllSensorsGeneric but uses ctx for the request
+func (w *Client) AllSensorsGenericContext(ctx context.Context) (sr interface{}, err error) {
+	return w.SensorsGenericContext(ctx, nil)
+}
+
 // Sensors gets sensors for one or more sensor IDs provided
 func (w *Client) Sensors(sensors []int) (sr SensorsResponse, err error) {
+	return w.SensorsContext(context.Background(), sensors)
+}
+
+// SensorsContext is like Sensors but uses ctx for the request
+func (w *Client) SensorsContext(ctx context.Context, sensors []int) (sr SensorsResponse, err error) {
 
 	csv := intArrToCSV(sensors)
 
@@ -291,13 +426,14 @@ func (w *Client) Sensors(sensors []int) (sr SensorsResponse, err error) {
 		sp.Add("sensor-ids", csv)
 	}
 
-	resp, err := w.get(fmt.Sprintf(sensorsPathFmt, csv), sp)
+	resp, err := w.get(ctx, fmt.Sprintf(sensorsPathFmt, csv), sp)
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		err = fmt.Errorf("Error making Sensors request. Got status: %v", resp.Status)
+		err = newAPIError("Sensors", resp, nil, sensors)
 		return
 	}
 
@@ -312,6 +448,111 @@ func (w *Client) Sensors(sensors []int) (sr SensorsResponse, err error) {
 // Sensors gets sensors for one or more sensor IDs provided
 // The result is an interface{} for generic use
 func (w *Client) SensorsGeneric(sensors []int) (sr interface{}, err error) {
+	return w.SensorsGenericContext(context.Background(), sensors)
+}
+
+// SensorsGenericContext is like SensorsGeneric but uses ctx for the request
+func (w *Client) SensorsGenericContext(ctx context.Context, sensors []int) (sr interface{}, err error) {
+
+	csv := intArrToCSV(sensors)
+
+	sp := w.MakeSignatureParams()
+	if sensors != nil {
+		sp.Add("sensor-ids", csv)
+	}
+
+	resp, err := w.get(ctx, fmt.Sprintf(sensorsPathFmt, csv), sp)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err = newAPIError("Sensors", resp, nil, sensors)
+		return
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&sr)
+	if err != nil {
+		return
+	}
+
+	return sr, nil
+}
+
+// SensorActivityResponse represents data from the /sensor-activity endpoint
+type SensorActivityResponse struct {
+	SensorActivity []struct {
+		Lsid         int   `json:"lsid"`
+		TimeReceived int64 `json:"time_received"`
+		TimeRecorded int64 `json:"time_recorded"`
+	} `json:"sensor_activity"`
+	GeneratedAt int `json:"generated_at"`
+}
+
+// AllSensorActivity gets the time data was last received for all sensors associated with your API Key
+func (w *Client) AllSensorActivity() (sr SensorActivityResponse, err error) {
+	return w.SensorActivity(nil)
+}
+
+// AllSensorActivityContext is like AllSensorActivity but uses ctx for the request
+func (w *Client) AllSensorActivityContext(ctx context.Context) (sr SensorActivityResponse, err error) {
+	return w.SensorActivityContext(ctx, nil)
+}
+
+// AllSensorActivityGeneric gets the time data was last received for all sensors associated with your API Key
+// The result is an interface{} for generic use
+func (w *Client) AllSensorActivityGeneric() (sr interface{}, err error) {
+	return w.SensorActivityGeneric(nil)
+}
+
+// AllSensorActivityGenericContext is like AllSensorActivityGeneric but uses ctx for the request
+func (w *Client) AllSensorActivityGenericContext(ctx context.Context) (sr interface{}, err error) {
+	return w.SensorActivityGenericContext(ctx, nil)
+}
+
+// SensorActivity gets the time data was last received for one or more sensor IDs provided
+func (w *Client) SensorActivity(sensors []int) (sr SensorActivityResponse, err error) {
+	return w.SensorActivityContext(context.Background(), sensors)
+}
+
+// SensorActivityContext is like SensorActivity but uses ctx for the request
+func (w *Client) SensorActivityContext(ctx context.Context, sensors []int) (sr SensorActivityResponse, err error) {
+
+	csv := intArrToCSV(sensors)
+
+	sp := w.MakeSignatureParams()
+	if sensors != nil {
+		sp.Add("sensor-ids", csv)
+	}
+
+	resp, err := w.get(ctx, fmt.Sprintf(activityPathFmt, csv), sp)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err = newAPIError("SensorActivity", resp, nil, sensors)
+		return
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&sr)
+	if err != nil {
+		return
+	}
+
+	return sr, nil
+}
+
+// SensorActivityGeneric gets the time data was last received for one or more sensor IDs provided
+// The result is an interface{} for generic use
+func (w *Client) SensorActivityGeneric(sensors []int) (sr interface{}, err error) {
+	return w.SensorActivityGenericContext(context.Background(), sensors)
+}
+
+// SensorActivityGenericContext is like SensorActivityGeneric but uses ctx for the request
+func (w *Client) SensorActivityGenericContext(ctx context.Context, sensors []int) (sr interface{}, err error) {
 
 	csv := intArrToCSV(sensors)
 
@@ -320,13 +561,14 @@ func (w *Client) SensorsGeneric(sensors []int) (sr interface{}, err error) {
 		sp.Add("sensor-ids", csv)
 	}
 
-	resp, err := w.get(fmt.Sprintf(sensorsPathFmt, csv), sp)
+	resp, err := w.get(ctx, fmt.Sprintf(activityPathFmt, csv), sp)
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		err = fmt.Errorf("Error making Sensors request. Got status: %v", resp.Status)
+		err = newAPIError("SensorActivity", resp, nil, sensors)
 		return
 	}
 
@@ -338,92 +580,142 @@ func (w *Client) SensorsGeneric(sensors []int) (sr interface{}, err error) {
 	return sr, nil
 }
 
+// NodesResponse represents data from the /nodes endpoint
+type NodesResponse struct {
+	Nodes []struct {
+		DeviceID        int     `json:"device_id"`
+		DeviceIDHex     string  `json:"device_id_hex"`
+		NodeName        string  `json:"node_name"`
+		StationID       int     `json:"station_id"`
+		StationName     string  `json:"station_name"`
+		ProductNumber   string  `json:"product_number"`
+		FirmwareVersion string  `json:"firmware_version"`
+		Active          bool    `json:"active"`
+		RegisteredDate  int     `json:"registered_date"`
+		Latitude        float64 `json:"latitude"`
+		Longitude       float64 `json:"longitude"`
+		Elevation       float64 `json:"elevation"`
+	} `json:"nodes"`
+	GeneratedAt int `json:"generated_at"`
+}
+
+// AllNodes gets all nodes (WeatherLink Live and Vantage Connect gateways) associated with your API Key
+func (w *Client) AllNodes() (nr NodesResponse, err error) {
+	return w.Nodes(nil)
+}
+
+// AllNodesContext is like AllNodes but uses ctx for the request
+func (w *Client) AllNodesContext(ctx context.Context) (nr NodesResponse, err error) {
+	return w.NodesContext(ctx, nil)
+}
+
+// AllNodesGeneric gets all nodes associated with your API Key
+// The result is an interface{} for generic use
+func (w *Client) AllNodesGeneric() (nr interface{}, err error) {
+	return w.NodesGeneric(nil)
+}
+
+// AllNodesGenericContext is like AllNodesGeneric but uses ctx for the request
+func (w *Client) AllNodesGenericContext(ctx context.Context) (nr interface{}, err error) {
+	return w.NodesGenericContext(ctx, nil)
+}
+
+// Nodes gets nodes for one or more node IDs provided
+func (w *Client) Nodes(nodes []int) (nr NodesResponse, err error) {
+	return w.NodesContext(context.Background(), nodes)
+}
+
+// NodesContext is like Nodes but uses ctx for the request
+func (w *Client) NodesContext(ctx context.Context, nodes []int) (nr NodesResponse, err error) {
+
+	csv := intArrToCSV(nodes)
+
+	sp := w.MakeSignatureParams()
+	if nodes != nil {
+		sp.Add("node-ids", csv)
+	}
+
+	resp, err := w.get(ctx, fmt.Sprintf(nodesPathFmt, csv), sp)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err = newAPIError("Nodes", resp, nil, nil)
+		return
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&nr)
+	if err != nil {
+		return
+	}
+
+	return nr, nil
+}
+
+// NodesGeneric gets nodes for one or more node IDs provided
+// The result is an interface{} for generic use
+func (w *Client) NodesGeneric(nodes []int) (nr interface{}, err error) {
+	return w.NodesGenericContext(context.Background(), nodes)
+}
+
+// NodesGenericContext is like NodesGeneric but uses ctx for the request
+func (w *Client) NodesGenericContext(ctx context.Context, nodes []int) (nr interface{}, err error) {
+
+	csv := intArrToCSV(nodes)
+
+	sp := w.MakeSignatureParams()
+	if nodes != nil {
+		sp.Add("node-ids", csv)
+	}
+
+	resp, err := w.get(ctx, fmt.Sprintf(nodesPathFmt, csv), sp)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err = newAPIError("Nodes", resp, nil, nil)
+		return
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&nr)
+	if err != nil {
+		return
+	}
+
+	return nr, nil
+}
+
 // CurrentResponse represents data from the /current endpoint
 type CurrentResponse struct {
-	StationID int `json:"station_id"`
-	Sensors   []struct {
-		Lsid              int `json:"lsid"`
-		SensorType        int `json:"sensor_type"`
-		DataStructureType int `json:"data_structure_type"`
-		Data              []struct {
-			Ts                int64       `json:"ts"`
-			BarTrend          float64     `json:"bar_trend"`
-			Bar               float64     `json:"bar"`
-			TempIn            float64     `json:"temp_in"`
-			HumIn             float64     `json:"hum_in"`
-			TempOut           float64     `json:"temp_out"`
-			WindSpeed         float64     `json:"wind_speed"`
-			WindSpeed10MinAvg float64     `json:"wind_speed_10_min_avg"`
-			WindDir           float64     `json:"wind_dir"`
-			TempExtra1        interface{} `json:"temp_extra_1"`
-			TempExtra2        interface{} `json:"temp_extra_2"`
-			TempExtra3        interface{} `json:"temp_extra_3"`
-			TempExtra4        interface{} `json:"temp_extra_4"`
-			TempExtra5        interface{} `json:"temp_extra_5"`
-			TempExtra6        interface{} `json:"temp_extra_6"`
-			TempExtra7        interface{} `json:"temp_extra_7"`
-			TempSoil1         interface{} `json:"temp_soil_1"`
-			TempSoil2         interface{} `json:"temp_soil_2"`
-			TempSoil3         interface{} `json:"temp_soil_3"`
-			TempSoil4         interface{} `json:"temp_soil_4"`
-			TempLeaf1         interface{} `json:"temp_leaf_1"`
-			TempLeaf2         interface{} `json:"temp_leaf_2"`
-			TempLeaf3         interface{} `json:"temp_leaf_3"`
-			TempLeaf4         interface{} `json:"temp_leaf_4"`
-			HumOut            float64     `json:"hum_out"`
-			HumExtra1         interface{} `json:"hum_extra_1"`
-			HumExtra2         interface{} `json:"hum_extra_2"`
-			HumExtra3         interface{} `json:"hum_extra_3"`
-			HumExtra4         interface{} `json:"hum_extra_4"`
-			HumExtra5         interface{} `json:"hum_extra_5"`
-			HumExtra6         interface{} `json:"hum_extra_6"`
-			HumExtra7         interface{} `json:"hum_extra_7"`
-			RainRateClicks    float64     `json:"rain_rate_clicks"`
-			RainRateIn        float64     `json:"rain_rate_in"`
-			RainRateMm        float64     `json:"rain_rate_mm"`
-			Uv                interface{} `json:"uv"`
-			SolarRad          interface{} `json:"solar_rad"`
-			RainStormClicks   float64     `json:"rain_storm_clicks"`
-			RainStormIn       float64     `json:"rain_storm_in"`
-			RainStormMm       float64     `json:"rain_storm_mm"`
-			RainDayClicks     float64     `json:"rain_day_clicks"`
-			RainDayIn         float64     `json:"rain_day_in"`
-			RainDayMm         float64     `json:"rain_day_mm"`
-			RainMonthClicks   float64     `json:"rain_month_clicks"`
-			RainMonthIn       float64     `json:"rain_month_in"`
-			RainMonthMm       float64     `json:"rain_month_mm"`
-			RainYearClicks    float64     `json:"rain_year_clicks"`
-			RainYearIn        float64     `json:"rain_year_in"`
-			RainYearMm        float64     `json:"rain_year_mm"`
-			EtDay             float64     `json:"et_day"`
-			EtMonth           float64     `json:"et_month"`
-			EtYear            float64     `json:"et_year"`
-			MoistSoil1        interface{} `json:"moist_soil_1"`
-			MoistSoil2        interface{} `json:"moist_soil_2"`
-			MoistSoil3        interface{} `json:"moist_soil_3"`
-			MoistSoil4        interface{} `json:"moist_soil_4"`
-			WetLeaf1          interface{} `json:"wet_leaf_1"`
-			WetLeaf2          interface{} `json:"wet_leaf_2"`
-			WetLeaf3          interface{} `json:"wet_leaf_3"`
-			WetLeaf4          interface{} `json:"wet_leaf_4"`
-		} `json:"data"`
-	} `json:"sensors"`
-	GeneratedAt int `json:"generated_at"`
+	StationID   int             `json:"station_id"`
+	Sensors     []CurrentSensor `json:"sensors"`
+	GeneratedAt int             `json:"generated_at"`
+	Raw         json.RawMessage `json:"-"` // the response as received
 }
 
 // Current gets current conditions data for one station
 func (w *Client) Current(station int) (cr CurrentResponse, err error) {
+	return w.CurrentContext(context.Background(), station)
+}
+
+// CurrentContext is like Current but uses ctx for the request
+func (w *Client) CurrentContext(ctx context.Context, station int) (cr CurrentResponse, err error) {
 
 	sp := w.MakeSignatureParams()
 	sp.Add("station-id", strconv.Itoa(station))
 
-	resp, err := w.get(fmt.Sprintf(currentPathFmt, station), sp)
+	resp, err := w.get(ctx, fmt.Sprintf(currentPathFmt, station), sp)
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		err = fmt.Errorf("Error making Current request. Got status: %v", resp.Status)
+		err = newAPIError("Current", resp, []int{station}, nil)
 		return
 	}
 
@@ -438,17 +730,23 @@ func (w *Client) Current(station int) (cr CurrentResponse, err error) {
 // CurrentGeneric gets current conditions data for one station
 // The result is an interface{} for generic use
 func (w *Client) CurrentGeneric(station int) (cr interface{}, err error) {
+	return w.CurrentGenericContext(context.Background(), station)
+}
+
+// CurrentGenericContext is like CurrentGeneric but uses ctx for the request
+func (w *Client) CurrentGenericContext(ctx context.Context, station int) (cr interface{}, err error) {
 
 	sp := w.MakeSignatureParams()
 	sp.Add("station-id", strconv.Itoa(station))
 
-	resp, err := w.get(fmt.Sprintf(currentPathFmt, station), sp)
+	resp, err := w.get(ctx, fmt.Sprintf(currentPathFmt, station), sp)
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		err = fmt.Errorf("Error making Current request. Got status: %v", resp.Status)
+		err = newAPIError("Current", resp, []int{station}, nil)
 		return
 	}
 
@@ -462,66 +760,31 @@ func (w *Client) CurrentGeneric(station int) (cr interface{}, err error) {
 
 // HistoricResponse represents historic data for one station ID within a given timerange
 type HistoricResponse struct {
-	Sensors []struct {
-		Lsid int `json:"lsid"`
-		Data []struct {
-			Ts               int64   `json:"ts"`
-			ArchInt          int     `json:"arch_int"`
-			RevType          int     `json:"rev_type"`
-			TempOut          float64 `json:"temp_out"`
-			TempOutHi        float64 `json:"temp_out_hi"`
-			TempOutLo        float64 `json:"temp_out_lo"`
-			TempIn           float64 `json:"temp_in"`
-			HumIn            float64 `json:"hum_in"`
-			HumOut           float64 `json:"hum_out"`
-			RainfallIn       float64 `json:"rainfall_in"`
-			RainfallClicks   float64 `json:"rainfall_clicks"`
-			RainfallMm       float64 `json:"rainfall_mm"`
-			RainRateHiIn     float64 `json:"rain_rate_hi_in"`
-			RainRateHiClicks float64 `json:"rain_rate_hi_clicks"`
-			RainRateHiMm     float64 `json:"rain_rate_hi_mm"`
-			Et               float64 `json:"et"`
-			Bar              float64 `json:"bar"`
-			WindNumSamples   float64 `json:"wind_num_samples"`
-			WindSpeedAvg     float64 `json:"wind_speed_avg"`
-			WindSpeedHi      float64 `json:"wind_speed_hi"`
-			WindDirOfHi      float64 `json:"wind_dir_of_hi"`
-			WindDirOfPrevail float64 `json:"wind_dir_of_prevail"`
-			ForecastRule     float64 `json:"forecast_rule"`
-			AbsPress         float64 `json:"abs_press"`
-			BarNoaa          float64 `json:"bar_noaa"`
-			DewPointOut      float64 `json:"dew_point_out"`
-			DewPointIn       float64 `json:"dew_point_in"`
-			Emc              float64 `json:"emc"`
-			HeatIndexOut     float64 `json:"heat_index_out"`
-			HeatIndexIn      float64 `json:"heat_index_in"`
-			WindChill        float64 `json:"wind_chill"`
-			WindRun          float64 `json:"wind_run"`
-			DegDaysHeat      float64 `json:"deg_days_heat"`
-			DegDaysCool      float64 `json:"deg_days_cool"`
-			ThwIndex         float64 `json:"thw_index"`
-			WetBulb          float64 `json:"wet_bulb"`
-		} `json:"data"`
-		SensorType        int `json:"sensor_type"`
-		DataStructureType int `json:"data_structure_type"`
-	} `json:"sensors"`
-	GeneratedAt int `json:"generated_at"`
-	StationID   int `json:"station_id"`
+	Sensors     []HistoricSensor `json:"sensors"`
+	GeneratedAt int              `json:"generated_at"`
+	StationID   int              `json:"station_id"`
+	Raw         json.RawMessage  `json:"-"` // the response as received
 }
 
 // Historic gets historic data for one station ID within a given timerange
 func (w *Client) Historic(station int, start time.Time, end time.Time) (hr HistoricResponse, err error) {
+	return w.HistoricContext(context.Background(), station, start, end)
+}
+
+// HistoricContext is like Historic but uses ctx for the request
+func (w *Client) HistoricContext(ctx context.Context, station int, start time.Time, end time.Time) (hr HistoricResponse, err error) {
 
 	sp := w.MakeSignatureParams()
 	sp.Add("station-id", strconv.Itoa(station))
 
-	resp, err := w.get(fmt.Sprintf(historicPathFmt, station, start.Unix(), end.Unix()), sp)
+	resp, err := w.get(ctx, fmt.Sprintf(historicPathFmt, station, start.Unix(), end.Unix()), sp)
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		err = fmt.Errorf("Error making Historic request. Got status: %v", resp.Status)
+		err = newAPIError("Historic", resp, []int{station}, nil)
 		return
 	}
 
@@ -536,17 +799,23 @@ func (w *Client) Historic(station int, start time.Time, end time.Time) (hr Histo
 // HistoricGeneric gets historic data for one station ID within a given timerange
 // The result is an interface{} for generic use
 func (w *Client) HistoricGeneric(station int, start time.Time, end time.Time) (hr interface{}, err error) {
+	return w.HistoricGenericContext(context.Background(), station, start, end)
+}
+
+// HistoricGenericContext is like HistoricGeneric but uses ctx for the request
+func (w *Client) HistoricGenericContext(ctx context.Context, station int, start time.Time, end time.Time) (hr interface{}, err error) {
 
 	sp := w.MakeSignatureParams()
 	sp.Add("station-id", strconv.Itoa(station))
 
-	resp, err := w.get(fmt.Sprintf(historicPathFmt, station, start.Unix(), end.Unix()), sp)
+	resp, err := w.get(ctx, fmt.Sprintf(historicPathFmt, station, start.Unix(), end.Unix()), sp)
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		err = fmt.Errorf("Error making Historic request. Got status: %v", resp.Status)
+		err = newAPIError("Historic", resp, []int{station}, nil)
 		return
 	}
 
@@ -558,16 +827,22 @@ func (w *Client) HistoricGeneric(station int, start time.Time, end time.Time) (h
 	return hr, nil
 }
 
-// SensorCatalog saves a catalogue of all types of sensors to file
-func (w *Client) SensorCatalog(path string) (err error) {
+// SaveSensorCatalog saves the raw catalogue of all types of sensors to file
+func (w *Client) SaveSensorCatalog(path string) (err error) {
+	return w.SaveSensorCatalogContext(context.Background(), path)
+}
+
+// SaveSensorCatalogContext is like SaveSensorCatalog but uses ctx for the request
+func (w *Client) SaveSensorCatalogContext(ctx context.Context, path string) (err error) {
 
-	resp, err := w.get(sensorCatalogPath, nil)
+	resp, err := w.get(ctx, sensorCatalogPath, nil)
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		err = fmt.Errorf("Error making SensorCatalog request. Got status: %v", resp.Status)
+		err = newAPIError("SensorCatalog", resp, nil, nil)
 		return
 	}
 
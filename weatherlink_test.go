@@ -2,6 +2,7 @@ package weatherlink
 
 import (
 	"testing"
+	"time"
 )
 
 func TestBuildURL(t *testing.T) {
@@ -23,6 +24,26 @@ func TestBuildURL(t *testing.T) {
 	}
 }
 
+func TestBuildURLBaseURL(t *testing.T) {
+
+	conf := &Config{
+		Key:     "mykey",
+		Secret:  "mysecret",
+		BaseURL: "http://localhost:8080/proxy/v2/",
+	}
+	wl := conf.NewClient()
+
+	p := wl.MakeSignatureParams()
+	p["foo"] = "bar"
+	p["t"] = "123"
+
+	got := wl.buildURL("/foo", p)
+	expect := "http://localhost:8080/proxy/v2/foo?api-key=mykey&api-signature=e576785c250d8c8db2e5fc2b7857b4c39ee56958107b978137e10d0fa6c1bc7b&t=123"
+	if got != expect {
+		t.Fatalf("Expected %v got %v", expect, got)
+	}
+}
+
 func TestSignatureParams(t *testing.T) {
 
 	conf := &Config{
@@ -41,3 +62,92 @@ func TestSignatureParams(t *testing.T) {
 		t.Fatalf("Expected %v got %v", expect, got)
 	}
 }
+
+func TestHistoricChunks(t *testing.T) {
+
+	start := time.Date(2020, 7, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour * 50)
+
+	got := historicChunks(start, end)
+	{
+		expect := 3
+		if len(got) != expect {
+			t.Fatalf("Expected %v got %v", expect, len(got))
+		}
+	}
+	{
+		expect := start.Add(time.Hour * 48)
+		if !got[2][0].Equal(expect) {
+			t.Fatalf("Expected %v got %v", expect, got[2][0])
+		}
+	}
+	{
+		expect := end
+		if !got[2][1].Equal(expect) {
+			t.Fatalf("Expected %v got %v", expect, got[2][1])
+		}
+	}
+}
+
+func TestStationLocation(t *testing.T) {
+	s := Station{TimeZone: "America/New_York"}
+	if _, err := time.LoadLocation(s.TimeZone); err != nil {
+		t.Skip(err)
+	}
+
+	{
+		expect := "America/New_York"
+		got := s.Location().String()
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := time.UTC
+		got := Station{TimeZone: "Nowhere/Special"}.Location()
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	// LoadLocation does not fall back to UTC
+	if _, err := (Station{TimeZone: "Nowhere/Special"}).LoadLocation(); err == nil {
+		t.Fatalf("Expected an error for an unknown time zone")
+	}
+	if _, err := (Station{}).LoadLocation(); err == nil {
+		t.Fatalf("Expected an error for a missing time zone")
+	}
+	if loc, err := s.LoadLocation(); err != nil || loc.String() != "America/New_York" {
+		t.Fatalf("Expected America/New_York got %v %v", loc, err)
+	}
+
+	// the day the clocks go back is 25 hours long
+	start, end := s.Day(time.Date(2020, 11, 1, 12, 0, 0, 0, time.UTC))
+
+	{
+		expect := time.Date(2020, 11, 1, 4, 0, 0, 0, time.UTC)
+		got := start
+		if !got.Equal(expect) {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	{
+		expect := 25 * time.Hour
+		got := end.Sub(start)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
+
+func TestDataHeaderTime(t *testing.T) {
+	var d SensorData = &VantageArchive{DataHeader: DataHeader{Ts: 1591981500}}
+
+	expect := time.Date(2020, 6, 12, 17, 5, 0, 0, time.UTC)
+	got := d.Time()
+	if !got.Equal(expect) {
+		t.Fatalf("Expected %v got %v", expect, got)
+	}
+}
@@ -2,9 +2,17 @@ package weatherlink_test
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -46,13 +54,399 @@ func TestCurrent(t *testing.T) {
 	}
 	{
 		expect := int64(1591894200)
-		got := c.Sensors[0].Data[0].Ts
+		got := c.Sensors[0].Data[0].Timestamp()
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	d, ok := c.Sensors[0].Data[0].(*weatherlink.VantageCurrent)
+	if !ok {
+		t.Fatalf("Expected *VantageCurrent got %T", c.Sensors[0].Data[0])
+	}
+	{
+		expect := weatherlink.NewFloat(216)
+		got := d.WindDir
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		// null in the response
+		got := d.TempExtra1
+		if got.Valid {
+			t.Fatalf("Expected missing value got %v", got)
+		}
+	}
+}
+
+func TestCurrentDerive(t *testing.T) {
+
+	conf := &weatherlink.Config{
+		Key:    "mykey",
+		Secret: "mysecret",
+		Client: &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(helperLoadBytes(t, "current.json"))),
+			}, nil
+		})}}
+
+	wl := conf.NewClient()
+
+	c, err := wl.Current(2970)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Derive(20.01288)
+
+	d := c.Sensors[0].Data[0].(*weatherlink.VantageCurrent)
+	if d.Derived == nil {
+		t.Fatalf("Expected derived values")
+	}
+	if got := d.Derived.DewPoint; !got.Valid || got.Value < 69.2 || got.Value > 69.5 {
+		t.Fatalf("Expected dew point of about 69.4 got %v", got)
+	}
+	if got := d.Derived.WindChill; got != d.TempOut {
+		t.Fatalf("Expected %v got %v", d.TempOut, got)
+	}
+	// no solar radiation sensor
+	if got := d.Derived.ThswIndex; got.Valid {
+		t.Fatalf("Expected missing value got %v", got)
+	}
+}
+
+func TestCurrentWeatherLinkLive(t *testing.T) {
+
+	conf := &weatherlink.Config{
+		Key:    "mykey",
+		Secret: "mysecret",
+		Client: &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(helperLoadBytes(t, "current-wll.json"))),
+			}, nil
+		})}}
+
+	wl := conf.NewClient()
+
+	c, err := wl.Current(3971)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range c.Sensors {
+		for _, data := range s.Data {
+			switch d := data.(type) {
+			case *weatherlink.ISSCurrent:
+				if d.Temp.Value != 62.7 || d.WindDirLast.Value != 346 || d.RainfallDailyIn.Value != 0.03 {
+					t.Fatalf("Unexpected ISS record %+v", d)
+				}
+			case *weatherlink.BarometerCurrent:
+				if d.BarSeaLevel.Value != 30.008 {
+					t.Fatalf("Unexpected barometer record %+v", d)
+				}
+			case *weatherlink.TempHumCurrent:
+				if d.TempIn.Value != 78 {
+					t.Fatalf("Unexpected temp/hum record %+v", d)
+				}
+			case *weatherlink.AirLinkCurrent:
+				if d.Pm2p5.Value != 3.4 || d.AqiDesc != "Good" {
+					t.Fatalf("Unexpected AirLink record %+v", d)
+				}
+			case *weatherlink.UnknownData:
+				if string(d.Fields["something_new"]) != "1.5" {
+					t.Fatalf("Unexpected unknown record %+v", d)
+				}
+			default:
+				t.Fatalf("Unexpected type %T for sensor type %v", d, s.SensorType)
+			}
+			if data.Timestamp() != 1602702900 {
+				t.Fatalf("Expected %v got %v", 1602702900, data.Timestamp())
+			}
+		}
+	}
+}
+
+func TestCurrentContextCanceled(t *testing.T) {
+
+	conf := &weatherlink.Config{
+		Key:    "mykey",
+		Secret: "mysecret",
+		Client: &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			if err := r.Context().Err(); err != nil {
+				return nil, err
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(helperLoadBytes(t, "current.json"))),
+			}, nil
+		})}}
+
+	wl := conf.NewClient()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := wl.CurrentContext(ctx, 2970)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected %v got %v", context.Canceled, err)
+	}
+}
+
+func TestBaseURL(t *testing.T) {
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/proxy/v2/stations" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(helperLoadBytes(t, "stations.json"))
+	}))
+	defer ts.Close()
+
+	conf := &weatherlink.Config{
+		Key:     "mykey",
+		Secret:  "mysecret",
+		BaseURL: ts.URL + "/proxy/v2",
+	}
+
+	wl := conf.NewClient()
+
+	s, err := wl.AllStations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	{
+		expect := 2970
+		got := s.Stations[0].StationID
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
+
+func TestAPIError(t *testing.T) {
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"code":"401","message":"Invalid API Signature"}`))
+	}))
+	defer ts.Close()
+
+	conf := &weatherlink.Config{
+		Key:     "mykey",
+		Secret:  "mysecret",
+		BaseURL: ts.URL,
+	}
+
+	wl := conf.NewClient()
+
+	_, err := wl.Current(2970)
+	if !weatherlink.IsUnauthorized(err) {
+		t.Fatalf("Expected unauthorized error got %v", err)
+	}
+	if weatherlink.IsNotFound(err) || weatherlink.IsRateLimited(err) {
+		t.Fatalf("Unexpected error classification for %v", err)
+	}
+
+	var apiErr *weatherlink.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError got %T", err)
+	}
+	{
+		expect := "Invalid API Signature"
+		got := apiErr.Message
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := "401"
+		got := apiErr.Code
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := "/current/2970"
+		got := apiErr.Path
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := 2970
+		got := apiErr.StationIDs[0]
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(helperLoadBytes(t, "current.json"))
+	}))
+	defer ts.Close()
+
+	conf := &weatherlink.Config{
+		Key:     "mykey",
+		Secret:  "mysecret",
+		BaseURL: ts.URL,
+		Retry: &weatherlink.RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+		},
+	}
+
+	wl := conf.NewClient()
+
+	c, err := wl.Current(2970)
+	if err != nil {
+		t.Fatal(err)
+	}
+	{
+		expect := 3
+		got := calls
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := 2970
+		got := c.StationID
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	// gives up after MaxAttempts and returns the last error
+	calls = -10
+	_, err = wl.Current(2970)
+	if !errors.As(err, new(*weatherlink.APIError)) {
+		t.Fatalf("Expected *APIError got %v", err)
+	}
+	{
+		expect := -7
+		got := calls
 		if got != expect {
 			t.Fatalf("Expected %v got %v", expect, got)
 		}
 	}
 }
 
+func TestCurrentFields(t *testing.T) {
+
+	body := helperLoadBytes(t, "current.json")
+	conf := &weatherlink.Config{
+		Key:    "mykey",
+		Secret: "mysecret",
+		Client: &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(body)),
+			}, nil
+		})}}
+
+	wl := conf.NewClient()
+
+	c, err := wl.Current(2970)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the typed response writes every record as it was received
+	type records struct {
+		Sensors []struct {
+			Data []map[string]interface{} `json:"data"`
+		} `json:"sensors"`
+	}
+	var expect, got records
+	if err := json.Unmarshal(body, &expect); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Fatalf("Expected %v got %v", expect, got)
+	}
+}
+
+func TestRetryPermanentError(t *testing.T) {
+
+	calls := 0
+	conf := &weatherlink.Config{
+		Key:    "mykey",
+		Secret: "mysecret",
+		Client: &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			return nil, errors.New("x509: certificate signed by unknown authority")
+		})},
+		Retry: &weatherlink.RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+		},
+	}
+
+	wl := conf.NewClient()
+
+	_, err := wl.Current(2970)
+	if err == nil {
+		t.Fatalf("Expected an error")
+	}
+	{
+		expect := 1
+		got := calls
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
+
+func TestLimiterSignsAfterWait(t *testing.T) {
+
+	var stale int64
+	conf := &weatherlink.Config{
+		Key:     "mykey",
+		Secret:  "mysecret",
+		Limiter: weatherlink.NewLimiter(0.5, 1),
+		Client: &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			ts, _ := strconv.ParseInt(r.URL.Query().Get("t"), 10, 64)
+			stale = time.Now().Unix() - ts
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(helperLoadBytes(t, "current.json"))),
+			}, nil
+		})}}
+
+	// use up the burst so the request waits two seconds for the limiter
+	if err := conf.Limiter.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	wl := conf.NewClient()
+
+	if _, err := wl.Current(2970); err != nil {
+		t.Fatal(err)
+	}
+	if stale > 1 {
+		t.Fatalf("Expected a fresh timestamp got one %v seconds old", stale)
+	}
+}
+
 func TestHistoric(t *testing.T) {
 
 	conf := &weatherlink.Config{
@@ -75,6 +469,18 @@ func TestHistoric(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	d, ok := c.Sensors[0].Data[0].(*weatherlink.VantageArchive)
+	if !ok {
+		t.Fatalf("Expected *VantageArchive got %T", c.Sensors[0].Data[0])
+	}
+	{
+		expect := weatherlink.NewFloat(80.6)
+		got := d.TempOut
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
 	{
 		expect := 2970
 		got := c.StationID
@@ -84,6 +490,207 @@ func TestHistoric(t *testing.T) {
 	}
 }
 
+func TestHistoricRange(t *testing.T) {
+
+	for _, concurrency := range []int{0, 2} {
+		var calls int32
+		conf := &weatherlink.Config{
+			Key:                 "mykey",
+			Secret:              "mysecret",
+			HistoricConcurrency: concurrency,
+			Client: &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+				atomic.AddInt32(&calls, 1)
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewReader(helperLoadBytes(t, "historic.json"))),
+				}, nil
+			})}}
+
+		wl := conf.NewClient()
+
+		end := time.Unix(1591984800, 0)
+		start := end.Add(-time.Hour * 60)
+
+		c, err := wl.HistoricRange(context.Background(), 2970, start, end)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expect := int32(3)
+			got := atomic.LoadInt32(&calls)
+			if got != expect {
+				t.Fatalf("Expected %v got %v", expect, got)
+			}
+		}
+		{
+			expect := 1
+			got := len(c.Sensors)
+			if got != expect {
+				t.Fatalf("Expected %v got %v", expect, got)
+			}
+		}
+		{
+			// every chunk returned the same records, so the duplicates are dropped
+			expect := 12
+			got := len(c.Sensors[0].Data)
+			if got != expect {
+				t.Fatalf("Expected %v got %v", expect, got)
+			}
+		}
+		for i := 1; i < len(c.Sensors[0].Data); i++ {
+			if c.Sensors[0].Data[i-1].Timestamp() >= c.Sensors[0].Data[i].Timestamp() {
+				t.Fatalf("Expected records in timestamp order")
+			}
+		}
+	}
+}
+
+func TestHistoricRangeFields(t *testing.T) {
+
+	// solar_rad_avg is not a field of a Vantage archive record, and most of its fields are missing
+	record := `{"ts":1591984800,"temp_out":80.6,"bar":null,"solar_rad_avg":250}`
+	body := `{"station_id":2970,"sensors":[{"lsid":12822,"data":[` + record + `],"sensor_type":37,"data_structure_type":4}],` +
+		`"generated_at":1591985000}`
+
+	conf := &weatherlink.Config{
+		Key:    "mykey",
+		Secret: "mysecret",
+		Client: &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader(body)),
+			}, nil
+		})}}
+
+	wl := conf.NewClient()
+
+	var expect map[string]interface{}
+	if err := json.Unmarshal([]byte(record), &expect); err != nil {
+		t.Fatal(err)
+	}
+
+	// one request or several, the record is written as it was received
+	end := time.Unix(1591984800, 0)
+	for _, span := range []time.Duration{time.Hour, 60 * time.Hour} {
+		c, err := wl.HistoricRange(context.Background(), 2970, end.Add(-span), end)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := json.Marshal(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out struct {
+			Sensors []struct {
+				Data []map[string]interface{} `json:"data"`
+			} `json:"sensors"`
+		}
+		if err := json.Unmarshal(b, &out); err != nil {
+			t.Fatal(err)
+		}
+		got := out.Sensors[0].Data[0]
+		if !reflect.DeepEqual(got, expect) {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
+
+func TestHistoricRangeError(t *testing.T) {
+
+	conf := &weatherlink.Config{
+		Key:                 "mykey",
+		Secret:              "mysecret",
+		HistoricConcurrency: 4,
+		Client: &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+			}, nil
+		})}}
+
+	wl := conf.NewClient()
+
+	end := time.Now()
+	start := end.Add(-time.Hour * 24 * 7)
+
+	_, err := wl.HistoricRange(context.Background(), 2970, start, end)
+	if !weatherlink.IsNotFound(err) {
+		t.Fatalf("Expected not found error got %v", err)
+	}
+}
+
+func TestIterateHistoric(t *testing.T) {
+
+	var calls int32
+	conf := &weatherlink.Config{
+		Key:    "mykey",
+		Secret: "mysecret",
+		Client: &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&calls, 1)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(helperLoadBytes(t, "historic.json"))),
+			}, nil
+		})}}
+
+	wl := conf.NewClient()
+
+	end := time.Unix(1591984800, 0)
+	start := end.Add(-time.Hour * 60)
+
+	it := wl.IterateHistoric(context.Background(), 2970, start, end)
+	defer it.Close()
+
+	n := 0
+	var last time.Time
+	for it.Next() {
+		r := it.Record()
+		if r.StationID != 2970 || r.Lsid != 12822 || r.SensorType != 37 {
+			t.Fatalf("Unexpected record %+v", r)
+		}
+		if !r.Time.After(last) {
+			t.Fatalf("Expected records in timestamp order")
+		}
+		last = r.Time
+		n++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	{
+		expect := 12
+		got := n
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := int32(3)
+		got := atomic.LoadInt32(&calls)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	// closing early stops further requests
+	atomic.StoreInt32(&calls, 0)
+	it = wl.IterateHistoric(context.Background(), 2970, start, end)
+	if !it.Next() {
+		t.Fatal(it.Err())
+	}
+	it.Close()
+	for it.Next() {
+	}
+	{
+		expect := int32(1)
+		got := atomic.LoadInt32(&calls)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
+
 func TestStations(t *testing.T) {
 
 	conf := &weatherlink.Config{
@@ -201,6 +808,137 @@ func TestSensors(t *testing.T) {
 
 }
 
+func TestNodes(t *testing.T) {
+
+	conf := &weatherlink.Config{
+		Key:    "mykey",
+		Secret: "mysecret",
+		Client: &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(helperLoadBytes(t, "nodes.json"))),
+			}, nil
+		})}}
+
+	wl := conf.NewClient()
+
+	n, err := wl.AllNodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	{
+		expect := 1
+		got := len(n.Nodes)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := "001D0BF19123"
+		got := n.Nodes[0].DeviceIDHex
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	n, err = wl.Nodes([]int{15834030})
+	if err != nil {
+		t.Fatal(err)
+	}
+	{
+		expect := 2970
+		got := n.Nodes[0].StationID
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
+
+func TestSensorActivity(t *testing.T) {
+
+	conf := &weatherlink.Config{
+		Key:    "mykey",
+		Secret: "mysecret",
+		Client: &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(helperLoadBytes(t, "sensor-activity.json"))),
+			}, nil
+		})}}
+
+	wl := conf.NewClient()
+
+	s, err := wl.SensorActivity([]int{12822, 12823})
+	if err != nil {
+		t.Fatal(err)
+	}
+	{
+		expect := 2
+		got := len(s.SensorActivity)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := int64(1591912500)
+		got := s.SensorActivity[0].TimeReceived
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
+
+func TestSensorCatalog(t *testing.T) {
+
+	conf := &weatherlink.Config{
+		Key:    "mykey",
+		Secret: "mysecret",
+		Client: &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(helperLoadBytes(t, "sensor-catalog.json"))),
+			}, nil
+		})}}
+
+	wl := conf.NewClient()
+
+	c, err := wl.SensorCatalog()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	st, ok := c.SensorType(37)
+	if !ok {
+		t.Fatalf("Expected sensor type 37")
+	}
+	{
+		expect := "Vantage Vue, Wireless"
+		got := st.ProductName
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	f, ok := c.Field(37, 2, "temp_out")
+	if !ok {
+		t.Fatalf("Expected field temp_out")
+	}
+	{
+		expect := "°F"
+		got := f.Units
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	if _, ok := c.Field(37, 3, "temp_out"); ok {
+		t.Fatalf("Expected no data structure type 3")
+	}
+	if _, ok := c.SensorType(1); ok {
+		t.Fatalf("Expected no sensor type 1")
+	}
+}
+
 func helperLoadBytes(t *testing.T, name string) []byte {
 	path := filepath.Join("testdata", name) // relative path
 	bytes, err := ioutil.ReadFile(path)
@@ -0,0 +1,130 @@
+package weatherlinktest
+
+import (
+	"math"
+	"time"
+
+	"github.com/alexhowarth/go-weatherlink"
+	"github.com/alexhowarth/go-weatherlink/derive"
+)
+
+// Series generates the historic records of one sensor. A record is made at every multiple of
+// Interval within the requested window, so the same time always gives the same record.
+type Series struct {
+	Lsid              int
+	SensorType        int
+	DataStructureType int
+	Interval          time.Duration                            // default 5 minutes
+	Data              func(t time.Time) weatherlink.SensorData // default SyntheticISSArchive for data structure type 11, SyntheticVantageArchive otherwise
+}
+
+// generate returns the records in the window (start, end]
+func (s Series) generate(start time.Time, end time.Time) weatherlink.HistoricSensor {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	data := s.Data
+	if data == nil {
+		data = func(t time.Time) weatherlink.SensorData { return SyntheticVantageArchive(t) }
+		if s.DataStructureType == 11 {
+			data = func(t time.Time) weatherlink.SensorData { return SyntheticISSArchive(t) }
+		}
+	}
+
+	hs := weatherlink.HistoricSensor{
+		Lsid:              s.Lsid,
+		SensorType:        s.SensorType,
+		DataStructureType: s.DataStructureType,
+		Data:              []weatherlink.SensorData{},
+	}
+	for t := start.Truncate(interval).Add(interval); !t.After(end); t = t.Add(interval) {
+		hs.Data = append(hs.Data, data(t))
+	}
+	return hs
+}
+
+// weather is a smooth, repeatable day of weather: coolest around 3am UTC and warmest around
+// 3pm, with wind and sun following the temperature
+type weather struct {
+	temp, hum, bar, wind, windDir, solar float64
+}
+
+func weatherAt(t time.Time) weather {
+	t = t.UTC()
+	hour := float64(t.Hour()) + float64(t.Minute())/60 + float64(t.Second())/3600
+	day := math.Sin(2 * math.Pi * (hour - 9) / 24)
+	week := math.Sin(2 * math.Pi * float64(t.Unix()) / (7 * 24 * 3600))
+	return weather{
+		temp:    round(60+10*day, 1),
+		hum:     round(70-20*day, 0),
+		bar:     round(30+0.2*week, 3),
+		wind:    round(6+4*day, 0),
+		windDir: round(math.Mod(225+45*week+360, 360), 0),
+		solar:   round(math.Max(0, 800*math.Sin(2*math.Pi*(hour-6)/24)), 0),
+	}
+}
+
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
+
+// SyntheticVantageArchive returns a generated Vantage archive record for the interval ending at t
+func SyntheticVantageArchive(t time.Time) *weatherlink.VantageArchive {
+	w := weatherAt(t)
+	d := &weatherlink.VantageArchive{
+		ArchInt:          300,
+		TempOut:          weatherlink.NewFloat(w.temp),
+		TempOutHi:        weatherlink.NewFloat(round(w.temp+0.2, 1)),
+		TempOutLo:        weatherlink.NewFloat(round(w.temp-0.2, 1)),
+		HumOut:           weatherlink.NewFloat(w.hum),
+		RainfallIn:       weatherlink.NewFloat(0),
+		RainRateHiIn:     weatherlink.NewFloat(0),
+		Et:               weatherlink.NewFloat(round(w.solar/800*0.002, 3)),
+		Bar:              weatherlink.NewFloat(w.bar),
+		WindSpeedAvg:     weatherlink.NewFloat(w.wind),
+		WindSpeedHi:      weatherlink.NewFloat(w.wind + 5),
+		WindDirOfHi:      weatherlink.NewFloat(round(w.windDir/22.5, 0)),
+		WindDirOfPrevail: weatherlink.NewFloat(round(w.windDir/22.5, 0)),
+		DewPointOut:      weatherlink.NewFloat(round(derive.DewPoint(w.temp, w.hum), 1)),
+		WindRun:          weatherlink.NewFloat(round(w.wind/12, 2)),
+	}
+	d.Ts = t.Unix()
+	return d
+}
+
+// SyntheticISSArchive returns a generated ISS archive record for the interval ending at t
+func SyntheticISSArchive(t time.Time) *weatherlink.ISSArchive {
+	w := weatherAt(t)
+	d := &weatherlink.ISSArchive{
+		TxID:             1,
+		TempLast:         weatherlink.NewFloat(w.temp),
+		TempAvg:          weatherlink.NewFloat(w.temp),
+		TempHi:           weatherlink.NewFloat(round(w.temp+0.2, 1)),
+		TempHiAt:         t.Unix() - 60,
+		TempLo:           weatherlink.NewFloat(round(w.temp-0.2, 1)),
+		TempLoAt:         t.Unix() - 240,
+		HumLast:          weatherlink.NewFloat(w.hum),
+		HumHi:            weatherlink.NewFloat(w.hum + 1),
+		HumLo:            weatherlink.NewFloat(w.hum - 1),
+		DewPointLast:     weatherlink.NewFloat(round(derive.DewPoint(w.temp, w.hum), 1)),
+		WindSpeedAvg:     weatherlink.NewFloat(w.wind),
+		WindSpeedHi:      weatherlink.NewFloat(w.wind + 5),
+		WindSpeedHiAt:    t.Unix() - 120,
+		WindSpeedHiDir:   weatherlink.NewFloat(w.windDir),
+		WindDirOfPrevail: weatherlink.NewFloat(w.windDir),
+		WindRun:          weatherlink.NewFloat(round(w.wind/12, 2)),
+		RainSize:         1,
+		RainfallClicks:   weatherlink.NewFloat(0),
+		RainfallIn:       weatherlink.NewFloat(0),
+		RainfallMm:       weatherlink.NewFloat(0),
+		RainRateHiIn:     weatherlink.NewFloat(0),
+		SolarRadAvg:      weatherlink.NewFloat(w.solar),
+		SolarRadHi:       weatherlink.NewFloat(w.solar),
+		Et:               weatherlink.NewFloat(round(w.solar/800*0.002, 3)),
+		Reception:        weatherlink.NewFloat(100),
+	}
+	d.Ts = t.Unix()
+	return d
+}
@@ -0,0 +1,448 @@
+// Package weatherlinktest provides a fake WeatherLink v2 API server for testing code that uses
+// the weatherlink package.
+//
+// The server checks the api-key, t and api-signature parameters of each request the way the
+// API does, serves the stations, sensors, current conditions and historic data it is given, and
+// can be made to fail, slow down or rate limit requests:
+//
+//	s := weatherlinktest.NewServer("mykey", "mysecret")
+//	defer s.Close()
+//	s.AddStation(weatherlink.Station{StationID: 123, TimeZone: "Europe/London"})
+//	s.AddHistoricSeries(123, weatherlinktest.Series{Lsid: 1, SensorType: 45, DataStructureType: 11, Interval: 5 * time.Minute})
+//	wl := s.Config().NewClient()
+package weatherlinktest
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexhowarth/go-weatherlink"
+)
+
+// MaxTimestampSkew is how far the t parameter may be from the server's clock
+const MaxTimestampSkew = 5 * time.Minute
+
+// Server is a fake WeatherLink v2 API. It is safe for concurrent use.
+type Server struct {
+	URL    string // base URL of the server, for Config.BaseURL
+	Key    string
+	Secret string
+
+	srv *httptest.Server
+
+	mu         sync.Mutex
+	stations   map[int]weatherlink.Station
+	sensors    map[int]weatherlink.Sensor
+	current    map[int]weatherlink.CurrentResponse
+	historic   map[int]weatherlink.HistoricResponse
+	series     map[int][]Series
+	failures   []failure
+	latency    time.Duration
+	rateLimit  int
+	ratePer    time.Duration
+	window     time.Time
+	windowUsed int
+	requests   int
+}
+
+type failure struct {
+	status int
+	n      int
+}
+
+// NewServer starts a Server accepting requests signed with key and secret. Close it when done.
+func NewServer(key string, secret string) *Server {
+	s := &Server{
+		Key:      key,
+		Secret:   secret,
+		stations: make(map[int]weatherlink.Station),
+		sensors:  make(map[int]weatherlink.Sensor),
+		current:  make(map[int]weatherlink.CurrentResponse),
+		historic: make(map[int]weatherlink.HistoricResponse),
+		series:   make(map[int][]Series),
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Config returns a Config for a client of the server
+func (s *Server) Config() *weatherlink.Config {
+	return &weatherlink.Config{
+		Key:     s.Key,
+		Secret:  s.Secret,
+		BaseURL: s.URL,
+	}
+}
+
+// Requests returns the number of requests received, including those that failed
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// AddStation adds or replaces a station
+func (s *Server) AddStation(st weatherlink.Station) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stations[st.StationID] = st
+}
+
+// AddSensor adds or replaces a sensor
+func (s *Server) AddSensor(sn weatherlink.Sensor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sensors[sn.Lsid] = sn
+}
+
+// SetCurrent sets the current conditions of a station
+func (s *Server) SetCurrent(station int, cr weatherlink.CurrentResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cr.StationID = station
+	s.current[station] = cr
+}
+
+// SetHistoric sets the historic data of a station. Requests are answered with the records
+// inside the requested window.
+func (s *Server) SetHistoric(station int, hr weatherlink.HistoricResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	hr.StationID = station
+	s.historic[station] = hr
+}
+
+// AddHistoricSeries adds a generated series to the historic data of a station
+func (s *Server) AddHistoricSeries(station int, series Series) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.series[station] = append(s.series[station], series)
+}
+
+// FailNext makes the next n requests fail with status
+func (s *Server) FailNext(n int, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failure{status: status, n: n})
+}
+
+// SetLatency delays every response by d
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// SetRateLimit allows n requests in each period, answering any more with 429 Too Many
+// Requests and a Retry-After header. A zero n removes the limit.
+func (s *Server) SetRateLimit(n int, per time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateLimit = n
+	s.ratePer = per
+	s.window = time.Time{}
+	s.windowUsed = 0
+}
+
+// admit counts a request and returns the status it should fail with, if any
+func (s *Server) admit(now time.Time) (status int, retryAfter time.Duration, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	latency = s.latency
+
+	if len(s.failures) > 0 {
+		f := &s.failures[0]
+		status = f.status
+		f.n--
+		if f.n <= 0 {
+			s.failures = s.failures[1:]
+		}
+		return status, 0, latency
+	}
+
+	if s.rateLimit > 0 {
+		if now.Sub(s.window) >= s.ratePer {
+			s.window = now
+			s.windowUsed = 0
+		}
+		if s.windowUsed >= s.rateLimit {
+			return http.StatusTooManyRequests, s.window.Add(s.ratePer).Sub(now), latency
+		}
+		s.windowUsed++
+	}
+	return 0, 0, latency
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	status, retryAfter, latency := s.admit(time.Now())
+
+	if latency > 0 {
+		t := time.NewTimer(latency)
+		select {
+		case <-r.Context().Done():
+			t.Stop()
+			return
+		case <-t.C:
+		}
+	}
+
+	if status != 0 {
+		if retryAfter > 0 {
+			secs := int((retryAfter + time.Second - 1) / time.Second)
+			w.Header().Set("Retry-After", strconv.Itoa(secs))
+		}
+		writeError(w, status, http.StatusText(status))
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	endpoint, arg := parts[0], ""
+	if len(parts) > 1 {
+		arg = parts[1]
+	}
+	if len(parts) > 2 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	if status, msg := s.authorize(r, endpoint, arg); status != 0 {
+		writeError(w, status, msg)
+		return
+	}
+
+	switch endpoint {
+	case "stations":
+		s.serveStations(w, arg)
+	case "sensors":
+		s.serveSensors(w, arg)
+	case "current":
+		s.serveCurrent(w, arg)
+	case "historic":
+		s.serveHistoric(w, r, arg)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// pathParams names the path parameter of each endpoint in the signature
+var pathParams = map[string]string{
+	"stations":        "station-ids",
+	"sensors":         "sensor-ids",
+	"sensor-activity": "sensor-ids",
+	"nodes":           "node-ids",
+	"current":         "station-id",
+	"historic":        "station-id",
+}
+
+// authorize checks the key, timestamp and signature of a request
+func (s *Server) authorize(r *http.Request, endpoint string, arg string) (int, string) {
+	q := r.URL.Query()
+	if q.Get("api-key") != s.Key {
+		return http.StatusUnauthorized, "invalid api-key"
+	}
+	t, err := strconv.ParseInt(q.Get("t"), 10, 64)
+	if err != nil {
+		return http.StatusUnauthorized, "missing or invalid t"
+	}
+	if d := time.Since(time.Unix(t, 0)); d > MaxTimestampSkew || d < -MaxTimestampSkew {
+		return http.StatusUnauthorized, "t is too far from the current time"
+	}
+
+	p := make(weatherlink.SignatureParams)
+	for k := range q {
+		if k != "api-signature" {
+			p[k] = q.Get(k)
+		}
+	}
+	if name, ok := pathParams[endpoint]; ok && arg != "" {
+		p[name] = arg
+	}
+	expect := p.Signature(s.Secret)
+	if !hmac.Equal([]byte(expect), []byte(q.Get("api-signature"))) {
+		return http.StatusUnauthorized, "invalid api-signature"
+	}
+	return 0, ""
+}
+
+// ids parses a comma separated list of ids, returning nil for an empty list
+func ids(arg string) ([]int, error) {
+	if arg == "" {
+		return nil, nil
+	}
+	var out []int
+	for _, v := range strings.Split(arg, ",") {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid id %q", v)
+		}
+		out = append(out, i)
+	}
+	return out, nil
+}
+
+func (s *Server) serveStations(w http.ResponseWriter, arg string) {
+	want, err := ids(arg)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.mu.Lock()
+	resp := weatherlink.StationsResponse{Stations: []weatherlink.Station{}, GeneratedAt: int(time.Now().Unix())}
+	if want == nil {
+		for _, st := range s.stations {
+			resp.Stations = append(resp.Stations, st)
+		}
+		sort.Slice(resp.Stations, func(a, b int) bool { return resp.Stations[a].StationID < resp.Stations[b].StationID })
+	}
+	for _, id := range want {
+		st, ok := s.stations[id]
+		if !ok {
+			s.mu.Unlock()
+			writeError(w, http.StatusForbidden, fmt.Sprintf("no access to station %d", id))
+			return
+		}
+		resp.Stations = append(resp.Stations, st)
+	}
+	s.mu.Unlock()
+	writeJSON(w, resp)
+}
+
+func (s *Server) serveSensors(w http.ResponseWriter, arg string) {
+	want, err := ids(arg)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.mu.Lock()
+	resp := weatherlink.SensorsResponse{Sensors: []weatherlink.Sensor{}, GeneratedAt: int(time.Now().Unix())}
+	if want == nil {
+		for _, sn := range s.sensors {
+			resp.Sensors = append(resp.Sensors, sn)
+		}
+		sort.Slice(resp.Sensors, func(a, b int) bool { return resp.Sensors[a].Lsid < resp.Sensors[b].Lsid })
+	}
+	for _, id := range want {
+		sn, ok := s.sensors[id]
+		if !ok {
+			s.mu.Unlock()
+			writeError(w, http.StatusForbidden, fmt.Sprintf("no access to sensor %d", id))
+			return
+		}
+		resp.Sensors = append(resp.Sensors, sn)
+	}
+	s.mu.Unlock()
+	writeJSON(w, resp)
+}
+
+func (s *Server) serveCurrent(w http.ResponseWriter, arg string) {
+	station, err := strconv.Atoi(arg)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid station-id")
+		return
+	}
+	s.mu.Lock()
+	cr, ok := s.current[station]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusForbidden, fmt.Sprintf("no access to station %d", station))
+		return
+	}
+	if cr.GeneratedAt == 0 {
+		cr.GeneratedAt = int(time.Now().Unix())
+	}
+	writeJSON(w, cr)
+}
+
+func (s *Server) serveHistoric(w http.ResponseWriter, r *http.Request, arg string) {
+	station, err := strconv.Atoi(arg)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid station-id")
+		return
+	}
+	q := r.URL.Query()
+	start, err1 := strconv.ParseInt(q.Get("start-timestamp"), 10, 64)
+	end, err2 := strconv.ParseInt(q.Get("end-timestamp"), 10, 64)
+	if err1 != nil || err2 != nil {
+		writeError(w, http.StatusBadRequest, "invalid start-timestamp or end-timestamp")
+		return
+	}
+	if end <= start {
+		writeError(w, http.StatusBadRequest, "end-timestamp must be after start-timestamp")
+		return
+	}
+	if time.Duration(end-start)*time.Second > weatherlink.MaxHistoricSpan {
+		writeError(w, http.StatusBadRequest, "the time range must not exceed 24 hours")
+		return
+	}
+
+	s.mu.Lock()
+	hr, hasFixture := s.historic[station]
+	series := append([]Series(nil), s.series[station]...)
+	_, hasStation := s.stations[station]
+	s.mu.Unlock()
+	if !hasFixture && len(series) == 0 && !hasStation {
+		writeError(w, http.StatusForbidden, fmt.Sprintf("no access to station %d", station))
+		return
+	}
+
+	resp := weatherlink.HistoricResponse{
+		StationID:   station,
+		Sensors:     []weatherlink.HistoricSensor{},
+		GeneratedAt: int(time.Now().Unix()),
+	}
+	for _, hs := range hr.Sensors {
+		out := hs
+		out.Data = []weatherlink.SensorData{}
+		for _, d := range hs.Data {
+			// archive records are timestamped at the end of their interval
+			if d.Timestamp() > start && d.Timestamp() <= end {
+				out.Data = append(out.Data, d)
+			}
+		}
+		resp.Sensors = append(resp.Sensors, out)
+	}
+	for _, sr := range series {
+		resp.Sensors = append(resp.Sensors, sr.generate(time.Unix(start, 0), time.Unix(end, 0)))
+	}
+	writeJSON(w, resp)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+// writeError writes an error body in the form the API uses
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}{strconv.Itoa(status), msg})
+}
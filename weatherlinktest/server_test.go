@@ -0,0 +1,292 @@
+package weatherlinktest_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alexhowarth/go-weatherlink"
+	"github.com/alexhowarth/go-weatherlink/weatherlinktest"
+)
+
+func helperLoad(t *testing.T, name string, v interface{}) {
+	t.Helper()
+	b, err := ioutil.ReadFile(filepath.Join("..", "testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStations(t *testing.T) {
+	s := weatherlinktest.NewServer("mykey", "mysecret")
+	defer s.Close()
+
+	s.AddStation(weatherlink.Station{StationID: 2, StationName: "two"})
+	s.AddStation(weatherlink.Station{StationID: 1, StationName: "one"})
+
+	wl := s.Config().NewClient()
+
+	all, err := wl.AllStations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	{
+		expect := 2
+		got := len(all.Stations)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := "one"
+		got := all.Stations[0].StationName
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	one, err := wl.Stations([]int{2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	{
+		expect := "two"
+		got := one.Stations[0].StationName
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	_, err = wl.Stations([]int{3})
+	if !weatherlink.IsForbidden(err) {
+		t.Fatalf("Expected forbidden got %v", err)
+	}
+}
+
+func TestSignature(t *testing.T) {
+	s := weatherlinktest.NewServer("mykey", "mysecret")
+	defer s.Close()
+
+	conf := s.Config()
+	conf.Secret = "wrong"
+	wl := conf.NewClient()
+
+	_, err := wl.AllStations()
+	if !weatherlink.IsUnauthorized(err) {
+		t.Fatalf("Expected unauthorized got %v", err)
+	}
+
+	conf = s.Config()
+	conf.Key = "wrong"
+	wl = conf.NewClient()
+
+	_, err = wl.AllStations()
+	if !weatherlink.IsUnauthorized(err) {
+		t.Fatalf("Expected unauthorized got %v", err)
+	}
+}
+
+func TestCurrent(t *testing.T) {
+	s := weatherlinktest.NewServer("mykey", "mysecret")
+	defer s.Close()
+
+	var cr weatherlink.CurrentResponse
+	helperLoad(t, "current.json", &cr)
+	s.SetCurrent(2970, cr)
+
+	wl := s.Config().NewClient()
+
+	c, err := wl.Current(2970)
+	if err != nil {
+		t.Fatal(err)
+	}
+	{
+		expect := cr.Sensors[0].Data[0].Timestamp()
+		got := c.Sensors[0].Data[0].Timestamp()
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := len(cr.Sensors)
+		got := len(c.Sensors)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
+
+func TestHistoric(t *testing.T) {
+	s := weatherlinktest.NewServer("mykey", "mysecret")
+	defer s.Close()
+
+	var hr weatherlink.HistoricResponse
+	helperLoad(t, "historic.json", &hr)
+	s.SetHistoric(2970, hr)
+
+	wl := s.Config().NewClient()
+
+	// the fixture runs from 1591981500 to 1591984800
+	h, err := wl.Historic(2970, time.Unix(1591981500, 0), time.Unix(1591983000, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	{
+		expect := 5
+		got := len(h.Sensors[0].Data)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := 80.7
+		got := h.Sensors[0].Data[0].(*weatherlink.VantageArchive).TempOut.Value
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	_, err = wl.Historic(2970, time.Unix(0, 0), time.Unix(1591983000, 0))
+	var apiErr *weatherlink.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Expected bad request got %v", err)
+	}
+}
+
+func TestHistoricSeries(t *testing.T) {
+	s := weatherlinktest.NewServer("mykey", "mysecret")
+	defer s.Close()
+
+	s.AddHistoricSeries(1, weatherlinktest.Series{Lsid: 10, SensorType: 45, DataStructureType: 11})
+	s.AddHistoricSeries(1, weatherlinktest.Series{Lsid: 11, SensorType: 37, DataStructureType: 4, Interval: 15 * time.Minute})
+
+	wl := s.Config().NewClient()
+
+	start := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	h, err := wl.HistoricRange(context.Background(), 1, start, start.Add(48*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	{
+		expect := 2
+		got := len(h.Sensors)
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	counts := map[int]int{}
+	for _, sn := range h.Sensors {
+		counts[sn.Lsid] = len(sn.Data)
+	}
+	{
+		expect := 576
+		got := counts[10]
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+	{
+		expect := 192
+		got := counts[11]
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+
+	// the same time always gives the same record
+	a := weatherlinktest.SyntheticISSArchive(start)
+	b := weatherlinktest.SyntheticISSArchive(start)
+	if a.TempAvg != b.TempAvg || a.HumLast != b.HumLast {
+		t.Fatalf("Expected %v got %v", a, b)
+	}
+}
+
+func TestSyntheticRounding(t *testing.T) {
+
+	// highs and lows are whole tenths, as a console reports them
+	tenths := func(f weatherlink.Float) bool {
+		return f.Valid && math.Round(f.Value*10)/10 == f.Value
+	}
+
+	start := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	for at := start; at.Before(start.Add(24 * time.Hour)); at = at.Add(5 * time.Minute) {
+		v := weatherlinktest.SyntheticVantageArchive(at)
+		if !tenths(v.TempOutHi) || !tenths(v.TempOutLo) {
+			t.Fatalf("Expected tenths got %v and %v at %v", v.TempOutHi, v.TempOutLo, at)
+		}
+		i := weatherlinktest.SyntheticISSArchive(at)
+		if !tenths(i.TempHi) || !tenths(i.TempLo) {
+			t.Fatalf("Expected tenths got %v and %v at %v", i.TempHi, i.TempLo, at)
+		}
+	}
+}
+
+func TestFailNext(t *testing.T) {
+	s := weatherlinktest.NewServer("mykey", "mysecret")
+	defer s.Close()
+
+	s.AddStation(weatherlink.Station{StationID: 1})
+	s.FailNext(2, http.StatusServiceUnavailable)
+
+	conf := s.Config()
+	conf.Retry = &weatherlink.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+	wl := conf.NewClient()
+
+	if _, err := wl.AllStations(); err != nil {
+		t.Fatal(err)
+	}
+	{
+		expect := 3
+		got := s.Requests()
+		if got != expect {
+			t.Fatalf("Expected %v got %v", expect, got)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	s := weatherlinktest.NewServer("mykey", "mysecret")
+	defer s.Close()
+
+	s.AddStation(weatherlink.Station{StationID: 1})
+	s.SetRateLimit(2, time.Minute)
+
+	wl := s.Config().NewClient()
+
+	for i := 0; i < 2; i++ {
+		if _, err := wl.AllStations(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err := wl.AllStations()
+	if !weatherlink.IsRateLimited(err) {
+		t.Fatalf("Expected rate limited got %v", err)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	s := weatherlinktest.NewServer("mykey", "mysecret")
+	defer s.Close()
+
+	s.AddStation(weatherlink.Station{StationID: 1})
+	s.SetLatency(time.Second)
+
+	wl := s.Config().NewClient()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := wl.AllStationsContext(ctx)
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+}